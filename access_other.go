@@ -0,0 +1,17 @@
+//go:build !unix
+
+package environ
+
+import (
+	"errors"
+	"os"
+)
+
+// canWrite checks the write permission bits of the path without writing to it
+func canWrite(_ string, info os.FileInfo) error {
+	if info.Mode().Perm()&0o222 == 0 {
+		return errors.New("permission denied")
+	}
+
+	return nil
+}
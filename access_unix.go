@@ -0,0 +1,16 @@
+//go:build unix
+
+package environ
+
+import (
+	"os"
+	"syscall"
+)
+
+// accessWrite is the W_OK mode of access(2)
+const accessWrite = 0x2
+
+// canWrite checks the write permission of the path without writing to it
+func canWrite(path string, _ os.FileInfo) error {
+	return syscall.Access(path, accessWrite)
+}
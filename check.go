@@ -0,0 +1,72 @@
+package environ
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// checkKey identifies a compiled check: the same expression
+// compiles differently depending on the types it runs against
+type checkKey struct {
+	expression string
+	value      reflect.Type
+	parent     reflect.Type
+}
+
+var checks sync.Map
+
+// compileCheck compiles the expression against the value's type and its sibling fields, if any.
+// Programs are cached, so each expression is compiled once per type.
+func compileCheck(expression string, value reflect.Type, parent reflect.Type) (*vm.Program, error) {
+	key := checkKey{expression, value, parent}
+	if program, ok := checks.Load(key); ok {
+		return program.(*vm.Program), nil
+	}
+
+	var zeroParent reflect.Value
+	if parent != nil {
+		zeroParent = reflect.Zero(parent)
+	}
+
+	program, err := expr.Compile(expression, expr.Env(checkEnv(reflect.Zero(value), zeroParent)), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile check '%s': %v", expression, err)
+	}
+
+	checks.Store(key, program)
+	return program, nil
+}
+
+// checkEnv exposes the value as `value`, and the sibling fields by their name
+func checkEnv(value reflect.Value, parent reflect.Value) map[string]any {
+	env := map[string]any{}
+
+	if parent.IsValid() {
+		for i := range parent.NumField() {
+			if field := parent.Type().Field(i); field.IsExported() {
+				env[field.Name] = parent.Field(i).Interface()
+			}
+		}
+	}
+
+	env["value"] = value.Interface()
+	return env
+}
+
+// runCheck evaluates the check and returns an error if it doesn't hold
+func runCheck(program *vm.Program, expression string, value reflect.Value, parent reflect.Value) error {
+	output, err := expr.Run(program, checkEnv(value, parent))
+	if err != nil {
+		return fmt.Errorf("%w. '%s': %v", ErrCheckFailed, expression, err)
+	}
+
+	if ok, _ := output.(bool); !ok {
+		return fmt.Errorf("%w. '%s' is false", ErrCheckFailed, expression)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package environ
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileCheck(t *testing.T) {
+	t.Run("compiles once per type", func(t *testing.T) {
+		first, err := compileCheck("value > 0", reflect.TypeFor[int](), nil)
+		assert.NoError(t, err)
+
+		second, err := compileCheck("value > 0", reflect.TypeFor[int](), nil)
+		assert.NoError(t, err)
+		assert.Same(t, first, second)
+
+		other, err := compileCheck("value > 0", reflect.TypeFor[float64](), nil)
+		assert.NoError(t, err)
+		assert.NotSame(t, first, other)
+	})
+
+	t.Run("returns error for invalid expression", func(t *testing.T) {
+		_, err := compileCheck("value >", reflect.TypeFor[int](), nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "value >")
+	})
+
+	t.Run("returns error for non boolean expression", func(t *testing.T) {
+		_, err := compileCheck("value + 1", reflect.TypeFor[int](), nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadCheck(t *testing.T) {
+	type Config struct {
+		MaxConns int `env:"name=MAX_CONNS, type=int, default=10"`
+		Conns    int `env:"name=CONNS, type=int, check=value % 2 == 0 && value <= MaxConns"`
+	}
+
+	t.Run("passes when the check holds", func(t *testing.T) {
+		os.Setenv("CONNS", "4")
+		defer os.Unsetenv("CONNS")
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.Conns)
+	})
+
+	t.Run("returns error with the expression", func(t *testing.T) {
+		os.Setenv("CONNS", "12")
+		defer os.Unsetenv("CONNS")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrCheckFailed)
+		assert.Contains(t, err.Error(), "CONNS")
+		assert.Contains(t, err.Error(), "value % 2 == 0 && value <= MaxConns")
+	})
+
+	t.Run("skips unset optional variables", func(t *testing.T) {
+		type Config struct {
+			Limit int `env:"name=RATE_LIMIT, type=int, optional, check=value > 0"`
+		}
+		os.Unsetenv("RATE_LIMIT")
+
+		_, err := Load[Config]()
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns tag error for invalid expression", func(t *testing.T) {
+		type Config struct {
+			Conns int `env:"name=CONNS, type=int, check=value <= Unknown"`
+		}
+
+		assert.ErrorIs(t, ValidateSpec[Config](), ErrInvalidTag)
+	})
+
+	t.Run("checks builder variables", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "3")
+		defer os.Unsetenv("TEST_VAR")
+
+		_, err := Int("TEST_VAR").Check("value % 2 == 0").Load()
+		assert.ErrorIs(t, err, ErrCheckFailed)
+
+		result, err := Int("TEST_VAR").Check("value < 5").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result)
+	})
+}
@@ -0,0 +1,171 @@
+package environ
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/AnatoleLucet/tiq"
+)
+
+// Dialect translates the tags of a struct field to an env tag (e.g. `envDefault:"8080"` to "default=8080").
+// It returns false when the field declares nothing.
+type Dialect func(field reflect.StructField) (tag string, ok bool, err error)
+
+// WithTagKey will read the env tags from another tag key (e.g. `config:"name=PORT, type=port"`)
+func WithTagKey(key string) Option {
+	return WithDialect(func(field reflect.StructField) (string, bool, error) {
+		tag, ok := field.Tag.Lookup(key)
+		return tag, ok, nil
+	})
+}
+
+// WithDialect will read the tags of the fields with the dialect instead of the env tag
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		o.dialect = dialect
+	}
+}
+
+// CaarlosEnvDialect reads the tags of github.com/caarlos0/env (`env:"PORT,required" envDefault:"8080"`).
+// The file option and slices and maps, which are separated by commas, aren't supported.
+func CaarlosEnvDialect(field reflect.StructField) (string, bool, error) {
+	env, ok := field.Tag.Lookup("env")
+	if !ok {
+		if prefix, ok := field.Tag.Lookup("envPrefix"); ok {
+			return joinTag("prefix", prefix)
+		}
+
+		return "", false, nil
+	}
+
+	if env == "-" {
+		return "-", true, nil
+	}
+
+	name, flags, _ := strings.Cut(env, ",")
+
+	required := false
+	tag := []string{"name=" + name}
+	for flag := range strings.SplitSeq(flags, ",") {
+		switch flag {
+		case "":
+		case "required", "notEmpty":
+			required = true
+		case "expand":
+			tag = append(tag, "expand")
+		default:
+			return "", false, fmt.Errorf("unsupported option %q", flag)
+		}
+	}
+
+	return translateTag(field, tag, required, map[string]string{"default": "envDefault"})
+}
+
+// EnvconfigDialect reads the tags of github.com/kelseyhightower/envconfig
+// (`envconfig:"port" default:"8080" required:"true"`). Like envconfig, every exported field is a variable
+// named after the field, and nested structs prefix their variables with their name.
+// Slices and maps, which are separated by commas, aren't supported.
+func EnvconfigDialect(field reflect.StructField) (string, bool, error) {
+	if !field.IsExported() {
+		return "", false, nil
+	}
+
+	name, ok := field.Tag.Lookup("envconfig")
+	switch {
+	case name == "-" || field.Tag.Get("ignored") == "true":
+		return "-", true, nil
+	case ok && name != "":
+		name = strings.ToUpper(name)
+	case field.Tag.Get("split_words") == "true":
+		name = screamingSnake(field.Name)
+	default:
+		name = strings.ToUpper(field.Name)
+	}
+
+	if t := field.Type; t.Kind() == reflect.Struct && guessTagType(t) == "" {
+		if field.Anonymous {
+			return "", true, nil
+		}
+
+		return joinTag("prefix", name+"_")
+	}
+
+	required := field.Tag.Get("required") == "true"
+	return translateTag(field, []string{"name=" + name}, required, map[string]string{"default": "default", "desc": "desc"})
+}
+
+// translateTag adds the type and optional flag of the field to the tag,
+// along with the options read from the other tags of the field (e.g. default from envDefault)
+func translateTag(field reflect.StructField, tag []string, required bool, options map[string]string) (string, bool, error) {
+	typ := guessTagType(field.Type)
+	if typ == TypeJson {
+		return "", false, fmt.Errorf("unsupported type %s", field.Type)
+	}
+	if typ != "" {
+		tag = append(tag, "type="+string(typ))
+	}
+
+	if !required {
+		tag = append(tag, "optional")
+	}
+
+	for _, option := range slices.Sorted(maps.Keys(options)) {
+		key := options[option]
+		value, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		if strings.Contains(value, ",") {
+			return "", false, fmt.Errorf("%s '%s' can't contain commas", key, value)
+		}
+
+		tag = append(tag, option+"="+value)
+	}
+
+	return strings.Join(tag, ", "), true, nil
+}
+
+func joinTag(option, value string) (string, bool, error) {
+	if strings.Contains(value, ",") {
+		return "", false, fmt.Errorf("%s '%s' can't contain commas", option, value)
+	}
+
+	return option + "=" + value, true, nil
+}
+
+// guessTagType returns the variable type of a field type, leaving enums to be detected
+func guessTagType(t reflect.Type) VariableType {
+	if inner, ok := maybeValueType(t); ok {
+		t = inner
+	}
+
+	if _, ok := lookupEnum(t); ok {
+		return ""
+	}
+
+	return guessType(t)
+}
+
+// translateField returns the field with its tags translated to an env tag by the dialect
+func translateField(field *tiq.Field, opts options) (*tiq.Field, error) {
+	if opts.dialect == nil {
+		return field, nil
+	}
+
+	tag, ok, err := opts.dialect(field.StructField)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := *field
+	translated.StructField.Tag = ""
+	if ok {
+		translated.StructField.Tag = reflect.StructTag(fmt.Sprintf("env:%q", tag))
+	}
+
+	return &translated, nil
+}
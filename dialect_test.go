@@ -0,0 +1,105 @@
+package environ
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTagKey(t *testing.T) {
+	type Config struct {
+		Port int    `config:"name=PORT, type=port, default=8080"`
+		Host string `env:"name=HOST"`
+	}
+
+	result, err := Load[Config](WithSource(Map{"PORT": "3000"}), WithTagKey("config"))
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Port: 3000}, result)
+}
+
+func TestCaarlosEnvDialect(t *testing.T) {
+	type DB struct {
+		Host string `env:"HOST,required"`
+	}
+
+	type Config struct {
+		Port    int           `env:"PORT" envDefault:"8080"`
+		Debug   bool          `env:"DEBUG"`
+		Timeout time.Duration `env:"TIMEOUT,notEmpty"`
+		Url     string        `env:"URL,expand" envDefault:"http://${HOST}"`
+		DB      DB            `envPrefix:"DB_"`
+		Skipped string        `env:"-"`
+		Other   string
+	}
+
+	t.Run("loads caarlos0/env tags", func(t *testing.T) {
+		source := Map{"TIMEOUT": "5s", "HOST": "example.com", "DB_HOST": "db", "OTHER": "other"}
+
+		result, err := Load[Config](WithSource(source), WithDialect(CaarlosEnvDialect))
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 8080, Timeout: 5 * time.Second, Url: "http://example.com", DB: DB{Host: "db"}}, result)
+	})
+
+	t.Run("returns error for missing required variables", func(t *testing.T) {
+		_, err := Load[Config](WithSource(Map{"DB_HOST": "db"}), WithDialect(CaarlosEnvDialect))
+		assert.ErrorIs(t, err, ErrMissingValue)
+		assert.ErrorContains(t, err, `"TIMEOUT"`)
+	})
+
+	t.Run("returns error for unsupported tags", func(t *testing.T) {
+		type Config struct {
+			Key   string   `env:"KEY,file"`
+			Hosts []string `env:"HOSTS"`
+			Tags  string   `env:"TAGS" envDefault:"a,b"`
+		}
+
+		err := ValidateSpec[Config](WithDialect(CaarlosEnvDialect))
+		assert.ErrorIs(t, err, ErrInvalidTag)
+		assert.ErrorContains(t, err, `"Key"`)
+		assert.ErrorContains(t, err, `"Hosts"`)
+		assert.ErrorContains(t, err, `"Tags"`)
+	})
+}
+
+func TestEnvconfigDialect(t *testing.T) {
+	type DB struct {
+		Host         string `required:"true"`
+		MaxOpenConns int    `split_words:"true" default:"10"`
+	}
+
+	type Config struct {
+		Port     int `envconfig:"listen_port" default:"8080" desc:"Listen port"`
+		Debug    bool
+		DB       DB
+		Ignored  string `ignored:"true"`
+		Skipped  string `envconfig:"-"`
+		internal string
+	}
+
+	t.Run("loads envconfig tags", func(t *testing.T) {
+		source := Map{"DEBUG": "true", "DB_HOST": "db", "IGNORED": "ignored", "INTERNAL": "internal"}
+
+		result, err := Load[Config](WithSource(source), WithDialect(EnvconfigDialect))
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 8080, Debug: true, DB: DB{Host: "db", MaxOpenConns: 10}}, result)
+	})
+
+	t.Run("describes translated tags", func(t *testing.T) {
+		result, err := Describe[Config](WithDialect(EnvconfigDialect))
+		assert.NoError(t, err)
+		assert.Equal(t, Description{Field: "Port", Name: "LISTEN_PORT", Type: TypeInt, Default: "8080", Optional: true, Description: "Listen port"}, result[0])
+		assert.Equal(t, "DB_MAX_OPEN_CONNS", result[3].Name)
+		assert.False(t, result[2].Optional)
+	})
+
+	t.Run("translates tags", func(t *testing.T) {
+		field, _ := reflect.TypeFor[Config]().FieldByName("Port")
+
+		tag, ok, err := EnvconfigDialect(field)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "name=LISTEN_PORT, type=int, optional, default=8080, desc=Listen port", tag)
+	})
+}
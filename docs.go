@@ -0,0 +1,104 @@
+package environ
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Description documents a variable declared on a struct
+type Description struct {
+	Field       string       `json:"field"`
+	Name        string       `json:"name"`
+	Aliases     []string     `json:"aliases,omitempty"`
+	Deprecated  []string     `json:"deprecated,omitempty"`
+	Type        VariableType `json:"type"`
+	Default     string       `json:"default,omitempty"` // as written in the tag
+	Optional    bool         `json:"optional"`
+	Description string       `json:"description,omitempty"`
+	Choices     []string     `json:"choices,omitempty"`
+	Static      bool         `json:"static,omitempty"`
+}
+
+// Describe returns the description of every variable declared on T, named as they would be loaded with opts.
+// The result can be encoded as JSON to be used as a schema, or rendered with Markdown.
+func Describe[T any](opts ...Option) ([]Description, error) {
+	var t T
+
+	fields, _, err := walkStruct(reflect.ValueOf(&t).Elem(), "", newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := []Description{}
+	for _, field := range fields {
+		descriptions = append(descriptions, describe(field.Path, field.Variable))
+	}
+
+	return descriptions, nil
+}
+
+func describe(field string, variable *Variable[any]) Description {
+	d := Description{
+		Field:       field,
+		Name:        variable.Name,
+		Type:        variable.Type,
+		Optional:    variable.Optional,
+		Description: variable.Description,
+		Static:      variable.Static,
+	}
+
+	if len(variable.Aliases) > 0 {
+		d.Aliases = variable.Aliases
+	}
+
+	if len(variable.Deprecated) > 0 {
+		d.Deprecated = variable.Deprecated
+	}
+
+	if d.Type == "" {
+		d.Type = TypeString
+	}
+
+	// the default is described as written in the tag (e.g. "1m" rather than the "1m0s" it's converted to)
+	if variable.rawDefault != "" {
+		d.Default = variable.rawDefault
+	} else if variable.Default != nil {
+		d.Default = fmt.Sprint(*variable.Default)
+	}
+
+	for _, choice := range variable.Oneof {
+		d.Choices = append(d.Choices, fmt.Sprint(choice))
+	}
+
+	if e, ok := lookupEnum(variable.target); ok && len(d.Choices) == 0 {
+		d.Choices = e.Choices()
+	}
+
+	return d
+}
+
+// Markdown renders the descriptions as a markdown table
+func Markdown(descriptions []Description) string {
+	var b strings.Builder
+
+	b.WriteString("| Name | Type | Required | Default | Choices | Reloadable | Description |\n")
+	b.WriteString("| ---- | ---- | -------- | ------- | ------- | ---------- | ----------- |\n")
+
+	for _, d := range descriptions {
+		required := "yes"
+		if d.Optional || d.Default != "" {
+			required = "no"
+		}
+
+		reloadable := "yes"
+		if d.Static {
+			reloadable = "no (restart required)"
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s | %s |\n",
+			d.Name, d.Type, required, d.Default, strings.Join(d.Choices, ", "), reloadable, d.Description)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package environ
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Run("describes every variable", func(t *testing.T) {
+		type Config struct {
+			Port    int        `env:"name=PORT, type=port, default=8080, static, desc=Listen port"`
+			Env     testAppEnv `env:"name=APP_ENV"`
+			Mode    string     `env:"name=MODE, oneof=a|b, optional"`
+			Ignored string
+		}
+
+		result, err := Describe[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, []Description{
+			{Field: "Port", Name: "PORT", Type: TypePort, Default: "8080", Description: "Listen port", Static: true},
+			{Field: "Env", Name: "APP_ENV", Type: TypeEnum, Choices: []string{"dev", "prod"}},
+			{Field: "Mode", Name: "MODE", Type: TypeString, Optional: true, Choices: []string{"a", "b"}},
+		}, result)
+	})
+
+	t.Run("describes defaults as written in the tag", func(t *testing.T) {
+		type Config struct {
+			Timeout time.Duration `env:"name=TIMEOUT, type=duration, default=1m"`
+			Api     url.URL       `env:"name=API_URL, type=url, default=https://api.example.com"`
+			Cutoff  time.Time     `env:"name=CUTOFF, type=date, default=2024-01-31"`
+		}
+
+		result, err := Describe[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, "1m", result[0].Default)
+		assert.Equal(t, "https://api.example.com", result[1].Default)
+		assert.Equal(t, "2024-01-31", result[2].Default)
+	})
+
+	t.Run("returns error for non struct", func(t *testing.T) {
+		_, err := Describe[int]()
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	result := Markdown([]Description{
+		{Name: "PORT", Type: TypePort, Default: "8080", Description: "Listen port", Static: true},
+		{Name: "APP_ENV", Type: TypeEnum, Choices: []string{"dev", "prod"}},
+	})
+
+	assert.Contains(t, result, "| `PORT` | port | no | 8080 |  | no (restart required) | Listen port |")
+	assert.Contains(t, result, "| `APP_ENV` | enum | yes |  | dev, prod | yes |  |")
+}
@@ -0,0 +1,113 @@
+package environ
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// enum holds the allowed values of an enum type and their aliases
+type enum struct {
+	values  []any
+	aliases map[string]any
+}
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[reflect.Type]enum{}
+)
+
+// RegisterEnum declares the values a type can be loaded as.
+// Values are matched case-insensitively against their fmt.Sprint
+// representation (so String() methods are honored), or against
+// one of the aliases (e.g. {"production": Prod}).
+//
+// Types with a `Values() []T` method (and optionally an `Aliases() map[string]T` method)
+// don't need to be registered.
+func RegisterEnum[T any](values []T, aliases map[string]T) {
+	e := enum{aliases: map[string]any{}}
+	for _, value := range values {
+		e.values = append(e.values, value)
+	}
+	for alias, value := range aliases {
+		e.aliases[alias] = value
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	enums[reflect.TypeFor[T]()] = e
+}
+
+// lookupEnum returns the enum registered for the type, or derived from its Values() method
+func lookupEnum(typ reflect.Type) (enum, bool) {
+	if typ == nil {
+		return enum{}, false
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	enumsMu.RLock()
+	e, ok := enums[typ]
+	enumsMu.RUnlock()
+	if ok {
+		return e, true
+	}
+
+	values, ok := callEnumMethod(typ, "Values", reflect.SliceOf(typ))
+	if !ok {
+		return enum{}, false
+	}
+
+	e = enum{aliases: map[string]any{}}
+	for i := range values.Len() {
+		e.values = append(e.values, values.Index(i).Interface())
+	}
+
+	if aliases, ok := callEnumMethod(typ, "Aliases", reflect.MapOf(reflect.TypeFor[string](), typ)); ok {
+		for iter := aliases.MapRange(); iter.Next(); {
+			e.aliases[iter.Key().String()] = iter.Value().Interface()
+		}
+	}
+
+	return e, true
+}
+
+func callEnumMethod(typ reflect.Type, name string, out reflect.Type) (reflect.Value, bool) {
+	method, ok := typ.MethodByName(name)
+	if !ok || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Type.Out(0) != out {
+		return reflect.Value{}, false
+	}
+
+	return method.Func.Call([]reflect.Value{reflect.Zero(typ)})[0], true
+}
+
+// Choices returns the name of every allowed value
+func (e enum) Choices() []string {
+	choices := make([]string, 0, len(e.values))
+	for _, value := range e.values {
+		choices = append(choices, fmt.Sprint(value))
+	}
+
+	return choices
+}
+
+// Parse returns the value matching v, ignoring case
+func (e enum) Parse(v string) (any, error) {
+	for _, value := range e.values {
+		if strings.EqualFold(fmt.Sprint(value), v) {
+			return value, nil
+		}
+	}
+
+	for alias, value := range e.aliases {
+		if strings.EqualFold(alias, v) {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w. '%s' is not one of: %s", ErrInvalidEnum, v, strings.Join(e.Choices(), "|"))
+}
@@ -0,0 +1,135 @@
+package environ
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAppEnv string
+
+const (
+	testEnvDev  testAppEnv = "dev"
+	testEnvProd testAppEnv = "prod"
+)
+
+func (testAppEnv) Values() []testAppEnv {
+	return []testAppEnv{testEnvDev, testEnvProd}
+}
+
+func (testAppEnv) Aliases() map[string]testAppEnv {
+	return map[string]testAppEnv{"production": testEnvProd, "development": testEnvDev}
+}
+
+type testLevel int
+
+const (
+	testLevelDebug testLevel = iota
+	testLevelInfo
+)
+
+func (l testLevel) String() string {
+	return [...]string{"debug", "info"}[l]
+}
+
+func TestLookupEnum(t *testing.T) {
+	t.Run("derives enum from Values method", func(t *testing.T) {
+		e, ok := lookupEnum(reflect.TypeFor[testAppEnv]())
+		assert.True(t, ok)
+		assert.Equal(t, []string{"dev", "prod"}, e.Choices())
+	})
+
+	t.Run("finds registered enum", func(t *testing.T) {
+		RegisterEnum([]testLevel{testLevelDebug, testLevelInfo}, nil)
+
+		e, ok := lookupEnum(reflect.TypeFor[*testLevel]())
+		assert.True(t, ok)
+		assert.Equal(t, []string{"debug", "info"}, e.Choices())
+	})
+
+	t.Run("returns false for other types", func(t *testing.T) {
+		_, ok := lookupEnum(reflect.TypeFor[string]())
+		assert.False(t, ok)
+	})
+}
+
+func TestEnumParse(t *testing.T) {
+	e, _ := lookupEnum(reflect.TypeFor[testAppEnv]())
+
+	t.Run("matches case-insensitively", func(t *testing.T) {
+		result, err := e.Parse("Prod")
+		assert.NoError(t, err)
+		assert.Equal(t, testEnvProd, result)
+	})
+
+	t.Run("matches aliases", func(t *testing.T) {
+		result, err := e.Parse("PRODUCTION")
+		assert.NoError(t, err)
+		assert.Equal(t, testEnvProd, result)
+	})
+
+	t.Run("returns error listing choices", func(t *testing.T) {
+		result, err := e.Parse("staging")
+		assert.ErrorIs(t, err, ErrInvalidEnum)
+		assert.Contains(t, err.Error(), "dev|prod")
+		assert.Nil(t, result)
+	})
+}
+
+func TestLoadEnum(t *testing.T) {
+	RegisterEnum([]testLevel{testLevelDebug, testLevelInfo}, map[string]testLevel{"verbose": testLevelDebug})
+
+	t.Run("loads enum fields", func(t *testing.T) {
+		type Config struct {
+			Env   testAppEnv `env:"name=APP_ENV"`
+			Level testLevel  `env:"name=LOG_LEVEL, type=enum"`
+		}
+		os.Setenv("APP_ENV", "Production")
+		os.Setenv("LOG_LEVEL", "INFO")
+		defer func() {
+			os.Unsetenv("APP_ENV")
+			os.Unsetenv("LOG_LEVEL")
+		}()
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, testEnvProd, result.Env)
+		assert.Equal(t, testLevelInfo, result.Level)
+	})
+
+	t.Run("returns error for unknown value", func(t *testing.T) {
+		type Config struct {
+			Env testAppEnv `env:"name=APP_ENV"`
+		}
+		os.Setenv("APP_ENV", "qa")
+		defer os.Unsetenv("APP_ENV")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrInvalidEnum)
+		assert.Contains(t, err.Error(), "APP_ENV")
+	})
+
+	t.Run("loads with builder", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "verbose")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := Enum[testLevel]("TEST_VAR").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, testLevelDebug, result)
+	})
+
+	t.Run("loads pointer with builder", func(t *testing.T) {
+		os.Setenv("APP_ENV", "Prod")
+		defer os.Unsetenv("APP_ENV")
+
+		result, err := Enum[*testAppEnv]("APP_ENV").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, testEnvProd, *result)
+
+		os.Setenv("APP_ENV", "qa")
+		_, err = Enum[*testAppEnv]("APP_ENV").Load()
+		assert.ErrorIs(t, err, ErrInvalidEnum)
+	})
+}
@@ -3,16 +3,20 @@ package environ
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/AnatoleLucet/tiq"
+	"github.com/expr-lang/expr/vm"
 )
 
-func Load[T any]() (T, error) {
-	return load[T]()
+func Load[T any](opts ...Option) (T, error) {
+	return load[T](newOptions(opts))
 }
 
-func MustLoad[T any]() T {
-	t, err := load[T]()
+func MustLoad[T any](opts ...Option) T {
+	t, err := load[T](newOptions(opts))
 	if err != nil {
 		panic(err)
 	}
@@ -20,41 +24,412 @@ func MustLoad[T any]() T {
 	return t
 }
 
-func load[T any]() (T, error) {
+// LoadInto loads the variables into the struct target points to, keeping the values already
+// in the struct when their variable is absent (e.g. a configuration read from a file).
+// See Loader.LoadInto.
+func LoadInto(target any, opts ...Option) error {
+	return defaultLoader.With(opts...).LoadInto(target)
+}
+
+func load[T any](opts options) (T, error) {
 	var t T
 
-	inspector, err := tiq.Inspect(&t)
+	err := loadStruct(reflect.ValueOf(&t).Elem(), opts)
+	return t, err
+}
+
+// loadStruct loads the variables declared on the struct and its nested structs, and validates them.
+// The struct must be addressable. With opts.overlay, fields that already have a value
+// are only replaced by the variables that are set (or their default with opts.overrideDefaults).
+func loadStruct(value reflect.Value, opts options) error {
+	fields, structs, err := walkStruct(value, "", opts)
 	if err != nil {
-		return t, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+		return err
+	}
+
+	// with opts.strict, the lookups are recorded to know the variables referenced by others
+	source, lookedUp := opts.source, map[string]bool{}
+	if opts.strict {
+		opts.source = &recorder{source, lookedUp}
+	}
+
+	var errs []error
+	loaded := []loadedVariable{}
+	for _, field := range fields {
+		variable := *field.Variable
+
+		// a field that already has a value doesn't need its variable
+		preset := opts.overlay && !field.Value.IsZero()
+		if preset {
+			variable.Optional = true
+		}
+
+		value, err := variable.loadMaybe(opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		l := loadedVariable{field.Variable, value}
+		loaded = append(loaded, l)
+
+		if preset && !l.isSet() && (!opts.overrideDefaults || variable.Default == nil) {
+			continue
+		}
+
+		if err := assignField(field.Field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%w for field %q: %v", ErrSetField, field.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if opts.strict {
+		if err := checkUnknown(source, opts.prefix+envPrefix(value), fields, lookedUp); err != nil {
+			return err
+		}
+	}
+
+	if err := checkRules(loaded); err != nil {
+		return err
+	}
+
+	if err := checkFields(fields, loaded); err != nil {
+		return err
 	}
 
+	return validateStructs(structs)
+}
+
+// ValidateSpec checks the env tags of every field of T without loading any variable.
+// It's meant to be called from unit tests to catch invalid tags (e.g. a default value that doesn't match the type).
+func ValidateSpec[T any](opts ...Option) error {
+	var t T
+
+	fields, _, err := walkStruct(reflect.ValueOf(&t).Elem(), "", newOptions(opts))
+
+	variables := make([]*Variable[any], 0, len(fields))
+	for _, field := range fields {
+		variables = append(variables, field.Variable)
+	}
+
+	return errors.Join(err, validateRules(variables))
+}
+
+// structField is a field declaring a variable, on the loaded struct or one of its nested structs
+type structField struct {
+	*tiq.Field
+	// Path is the field's path from the loaded struct (e.g. "DB.Host")
+	Path     string
+	Variable *Variable[any]
+
+	// parent is the struct holding the field
+	parent reflect.Value
+	// check is the compiled Variable.Check expression, if any
+	check *vm.Program
+}
+
+// structValue is the loaded struct or one of its nested structs
+type structValue struct {
+	reflect.Value
+	Path string
+}
+
+// walkStruct returns every field declaring a variable on the struct and its nested structs,
+// along with the structs themselves (nested structs first).
+// The names of the variables are prefixed with opts.prefix and the prefixes of the structs.
+// The struct must be addressable.
+func walkStruct(value reflect.Value, path string, opts options) ([]structField, []structValue, error) {
+	inspector, err := tiq.Inspect(value.Addr().Interface())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+
+	opts.prefix += envPrefix(value)
+
+	var errs []error
+	var fields []structField
+	var structs []structValue
 	for _, field := range inspector.Fields() {
-		variable, err := tiq.Parse[Variable[any]](field)
+		fieldPath := path + field.Name
+
+		field, err := translateField(field, opts)
 		if err != nil {
-			if errors.Is(err, tiq.ErrCompileTag) {
-				return t, fmt.Errorf("%w for field %q: %v", ErrInvalidTag, field.Name, err)
+			errs = append(errs, fmt.Errorf("%w for field %q: %v", ErrInvalidTag, fieldPath, err))
+			continue
+		}
+
+		variable, err := parseField(field, fieldPath, opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if variable.Name != "" {
+			f := structField{Field: field, Path: fieldPath, Variable: variable, parent: value}
+			if variable.Check != "" {
+				f.check, err = compileCheck(variable.Check, field.Value.Type(), value.Type())
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%w for field %q: %v", ErrInvalidTag, fieldPath, err))
+					continue
+				}
 			}
 
-			return t, fmt.Errorf("%w for field %q: %v", ErrUnexpected, field.Name, err)
+			fields = append(fields, f)
+			continue
 		}
 
-		if variable.Name == "" {
+		if isNestedStruct(field) {
+			nested, err := tiq.Parse[nestedTag](field)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w for field %q: %v", ErrInvalidTag, fieldPath, err))
+				continue
+			}
+
+			nestedOpts := opts
+			nestedOpts.prefix += nested.Prefix
+
+			nestedFields, nestedStructs, err := walkStruct(field.Value, fieldPath+".", nestedOpts)
+			errs = append(errs, err)
+			fields = append(fields, nestedFields...)
+			structs = append(structs, nestedStructs...)
+		}
+	}
+
+	// names are unique once every field of the loaded struct is walked
+	if path == "" {
+		errs = append(errs, checkCollisions(fields))
+	}
+
+	structs = append(structs, structValue{value, strings.TrimSuffix(path, ".")})
+	return fields, structs, errors.Join(errs...)
+}
+
+// envPrefix returns the prefix given by the struct's EnvPrefix method, if any
+func envPrefix(value reflect.Value) string {
+	if prefixer, ok := value.Addr().Interface().(interface{ EnvPrefix() string }); ok {
+		return prefixer.EnvPrefix()
+	}
+
+	return ""
+}
+
+// nestedTag is the env tag of a nested struct field
+type nestedTag struct {
+	// Prefix is prepended to the names of the nested struct's variables
+	Prefix string `tag:"env | get('prefix')"`
+}
+
+// isNestedStruct reports whether the field is a struct whose fields can declare variables
+func isNestedStruct(field *tiq.Field) bool {
+	if tag, _ := field.Tag("env"); tag == "-" {
+		return false
+	}
+
+	return field.IsExported() && field.Value.Kind() == reflect.Struct
+}
+
+// checkFields evaluates the check expression of the fields that were loaded.
+// loaded holds what was loaded for every field, in the same order.
+func checkFields(fields []structField, loaded []loadedVariable) error {
+	var errs []error
+	for i, field := range fields {
+		if field.check == nil || loaded[i].value.Value == nil {
 			continue
 		}
 
-		value, err := variable.Load()
+		if err := runCheck(field.check, field.Variable.Check, field.Value, field.parent); err != nil {
+			errs = append(errs, &VariableError{field.Variable.Name, err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateStructs calls the ValidateEnv() or Validate() method of the loaded structs
+func validateStructs(structs []structValue) error {
+	var errs []error
+	for _, s := range structs {
+		var err error
+		switch v := s.Addr().Interface().(type) {
+		case interface{ ValidateEnv() error }:
+			err = v.ValidateEnv()
+		case interface{ Validate() error }:
+			err = v.Validate()
+		}
+
 		if err != nil {
-			return t, err
+			path := s.Path
+			if path == "" {
+				path = s.Type().Name()
+			}
+
+			errs = append(errs, &StructError{path, err})
 		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseField parses the field's env tag into a variable
+func parseField(field *tiq.Field, path string, opts options) (*Variable[any], error) {
+	variable, err := tiq.Parse[Variable[any]](field)
+	if err != nil {
+		if errors.Is(err, tiq.ErrCompileTag) {
+			return nil, fmt.Errorf("%w for field %q: %v", ErrInvalidTag, path, err)
+		}
+
+		return nil, fmt.Errorf("%w for field %q: %v", ErrUnexpected, path, err)
+	}
+
+	// name=NEW_NAME|OLD_NAME declares aliases, tried in order
+	names := strings.Split(variable.Name, "|")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	variable.Name, variable.Aliases = names[0], names[1:]
+
+	variable.Expand = variable.Expand || opts.expand
+
+	variable.target = field.Value.Type()
+	if inner, ok := maybeValueType(variable.target); ok {
+		// Maybe fields are here to tell if the variable is absent, so it's never required
+		variable.target = inner
+		variable.Optional = true
+	}
+
+	if variable.Name == "" && namedFromPath(field, variable, opts) {
+		variable.Name = opts.namingStrategy(strings.Split(path, "."))
+		if _, ok := lookupEnum(variable.target); !ok && variable.Type == "" {
+			variable.Type = guessType(variable.target)
+		}
+	}
+
+	if variable.Name != "" {
+		*variable = variable.prefixed(opts.prefix)
+	}
+
+	// conditionally required variables are checked once every variable is loaded
+	if variable.RequiredIf != "" || variable.RequiredUnless != "" || len(variable.RequiredWith) > 0 {
+		variable.Optional = true
+	}
+
+	if _, ok := lookupEnum(variable.target); ok && variable.Type == "" {
+		variable.Type = TypeEnum
+	}
+
+	if err := variable.checkOptions(); err != nil {
+		return nil, fmt.Errorf("%w for field %q: %w", ErrInvalidTag, path, err)
+	}
+
+	if err := typeTagValues(variable); err != nil {
+		return nil, fmt.Errorf("%w for field %q: %w", ErrInvalidTag, path, err)
+	}
+
+	return variable, nil
+}
+
+// typeTagValues converts the oneof and default values written in the tag
+// to the variable's type, so they compare with the loaded value.
+// The default value must also satisfy the oneof constraint.
+func typeTagValues(variable *Variable[any]) error {
+	// paths are checked on the filesystem when the variable is loaded, not on this machine
+	opts := variable.typeOptions()
+	opts.Lexical = true
 
-		if value == nil {
+	for i, choice := range variable.Oneof {
+		raw, ok := choice.(string)
+		if !ok {
 			continue
 		}
 
-		if err := field.SetFrom(value); err != nil {
-			return t, fmt.Errorf("%w for field %q: %v", ErrSetField, field.Name, err)
+		typed, err := validateTypeWith[any](variable.Type, raw, opts)
+		if err != nil {
+			return fmt.Errorf("oneof choice '%s': %w", raw, err)
+		}
+
+		variable.Oneof[i] = typed
+	}
+
+	if variable.Default != nil {
+		// defaults referencing other variables are validated once expanded
+		raw, ok := (*variable.Default).(string)
+		variable.rawDefault = raw
+
+		if ok && !(variable.Expand && strings.Contains(raw, "${")) {
+			typed, err := validateWith(*variable, raw, opts)
+			if err != nil {
+				return fmt.Errorf("default '%s': %w", raw, err)
+			}
+
+			variable.Default = &typed
+		}
+	}
+
+	return nil
+}
+
+var (
+	urlType    = reflect.TypeFor[url.URL]()
+	urlPtrType = reflect.TypeFor[*url.URL]()
+)
+
+// setField sets the field to the loaded value, converting it to
+// the field's type when the value isn't directly assignable.
+func setField(field *tiq.Field, value any) error {
+	typ := field.Value.Type()
+
+	if str, ok := value.(string); ok && (typ == urlType || typ == urlPtrType) {
+		u, err := url.Parse(str)
+		if err != nil {
+			return err
+		}
+
+		value = u
+		if typ == urlType {
+			value = *u
+		}
+	}
+
+	if reflect.TypeOf(value).AssignableTo(typ) {
+		return field.Set(value)
+	}
+
+	return field.SetFrom(value)
+}
+
+// assignField sets the field to the loaded value
+func assignField(field *tiq.Field, value Maybe[any]) error {
+	if _, ok := maybeValueType(field.Value.Type()); ok {
+		return setMaybe(field, value)
+	}
+
+	// nothing was loaded (e.g. an unset optional variable), so pointer fields stay nil
+	if value.Value == nil {
+		return nil
+	}
+
+	return setField(field, value.Value)
+}
+
+// setMaybe sets the Value and Presence of a Maybe[T] field
+func setMaybe(field *tiq.Field, value Maybe[any]) error {
+	if !field.Value.CanSet() {
+		return tiq.ErrFieldNotSettable
+	}
+
+	if value.Value != nil {
+		structField, _ := field.Value.Type().FieldByName("Value")
+		inner := &tiq.Field{Value: field.Value.FieldByName("Value"), StructField: structField}
+
+		if err := setField(inner, value.Value); err != nil {
+			return err
 		}
 	}
 
-	return t, nil
+	field.Value.FieldByName("Presence").Set(reflect.ValueOf(value.Presence))
+	field.Value.FieldByName("Name").SetString(value.Name)
+	return nil
 }
@@ -0,0 +1,98 @@
+// Package environtest helps testing configurations loaded with environ without touching the process environment,
+// so tests can run in parallel.
+package environtest
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/AnatoleLucet/environ"
+)
+
+// Source returns an in-memory source holding a copy of env
+func Source(env map[string]string) environ.Source {
+	return environ.Map(maps.Clone(env))
+}
+
+// Loader returns a loader reading the variables from env instead of the process environment
+func Loader(env map[string]string, opts ...environ.Option) *environ.Loader {
+	return environ.Default().With(append(slices.Clip(opts), environ.WithSource(Source(env)))...)
+}
+
+// Load loads T from env instead of the process environment.
+// Invalid tags fail the test, as they're a bug of T rather than of env.
+func Load[T any](t testing.TB, env map[string]string, opts ...environ.Option) (T, error) {
+	t.Helper()
+
+	config, err := environ.Load[T](append(slices.Clip(opts), environ.WithSource(Source(env)))...)
+	if errors.Is(err, environ.ErrInvalidTag) {
+		t.Fatalf("invalid tags on %T: %v", config, err)
+	}
+
+	return config, err
+}
+
+// AssertMissing asserts that err reports the variable as missing
+func AssertMissing(t testing.TB, err error, name string) bool {
+	t.Helper()
+
+	for _, e := range variableErrors(err, name) {
+		if errors.Is(e, environ.ErrMissingValue) {
+			return true
+		}
+	}
+
+	t.Errorf("expected variable %q to be missing, got: %v", name, err)
+	return false
+}
+
+// AssertInvalid asserts that err reports the variable as invalid,
+// and that its error matches every target (e.g. environ.ErrInvalidPort)
+func AssertInvalid(t testing.TB, err error, name string, targets ...error) bool {
+	t.Helper()
+
+	for _, e := range variableErrors(err, name) {
+		if errors.Is(e, environ.ErrMissingValue) {
+			continue
+		}
+
+		if matchesAll(e, targets) {
+			return true
+		}
+	}
+
+	t.Errorf("expected variable %q to be invalid, got: %v", name, err)
+	return false
+}
+
+// variableErrors returns the errors of the variable found in err
+func variableErrors(err error, name string) []error {
+	var found []error
+	switch e := err.(type) {
+	case nil:
+	case *environ.VariableError:
+		if e.Name == name {
+			found = append(found, e)
+		}
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			found = append(found, variableErrors(err, name)...)
+		}
+	case interface{ Unwrap() error }:
+		found = variableErrors(e.Unwrap(), name)
+	}
+
+	return found
+}
+
+func matchesAll(err error, targets []error) bool {
+	for _, target := range targets {
+		if !errors.Is(err, target) {
+			return false
+		}
+	}
+
+	return true
+}
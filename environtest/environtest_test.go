@@ -0,0 +1,127 @@
+package environtest
+
+import (
+	"testing"
+
+	"github.com/AnatoleLucet/environ"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Host  string `env:"name=HOST"`
+	Port  int    `env:"name=PORT, type=port, default=8080"`
+	Level string `env:"name=LOG_LEVEL, oneof=debug|info, default=info"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads from the map", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := Load[testConfig](t, map[string]string{"HOST": "localhost", "PORT": "3000"})
+		assert.NoError(t, err)
+		assert.Equal(t, testConfig{Host: "localhost", Port: 3000, Level: "info"}, result)
+	})
+
+	t.Run("applies options", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := Load[testConfig](t, map[string]string{"APP_HOST": "localhost"}, environ.WithPrefix("APP_"))
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost", result.Host)
+	})
+
+	t.Run("leaves the options of the caller untouched", func(t *testing.T) {
+		t.Parallel()
+
+		// the spare capacity would receive the source if it was appended to opts
+		opts := make([]environ.Option, 1, 2)
+		opts[0] = environ.WithPrefix("APP_")
+
+		_, err := Load[testConfig](t, map[string]string{"APP_HOST": "localhost"}, opts...)
+		assert.NoError(t, err)
+		Loader(map[string]string{}, opts...)
+		assert.Nil(t, opts[:2][1])
+	})
+
+	t.Run("fails on invalid tags", func(t *testing.T) {
+		t.Parallel()
+
+		type Config struct {
+			Port int `env:"name=PORT, type=port, default=99999"`
+		}
+
+		mock := &mockT{TB: t}
+		func() {
+			defer func() { recover() }()
+			Load[Config](mock, map[string]string{})
+		}()
+		assert.True(t, mock.failed)
+	})
+}
+
+func TestLoadIgnoresEnvironment(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+
+	_, err := Load[testConfig](t, map[string]string{})
+	AssertMissing(t, err, "HOST")
+}
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	loader := Loader(map[string]string{"PORT": "3000"})
+
+	result, err := environ.Port("PORT").LoadFrom(loader)
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, result)
+
+	var config testConfig
+	AssertMissing(t, loader.Load(&config), "HOST")
+}
+
+func TestAssertMissing(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load[testConfig](t, map[string]string{"PORT": "abc"})
+
+	assert.True(t, AssertMissing(t, err, "HOST"))
+
+	mock := &mockT{TB: t}
+	assert.False(t, AssertMissing(mock, err, "PORT"))
+	assert.False(t, AssertMissing(mock, nil, "HOST"))
+	assert.True(t, mock.failed)
+}
+
+func TestAssertInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load[testConfig](t, map[string]string{"PORT": "abc", "LOG_LEVEL": "trace"})
+
+	assert.True(t, AssertInvalid(t, err, "PORT"))
+	assert.True(t, AssertInvalid(t, err, "PORT", environ.ErrInvalidPort))
+	assert.True(t, AssertInvalid(t, err, "LOG_LEVEL", environ.ErrNotInOneof))
+
+	mock := &mockT{TB: t}
+	assert.False(t, AssertInvalid(mock, err, "HOST"))
+	assert.False(t, AssertInvalid(mock, err, "PORT", environ.ErrNotInOneof))
+	assert.True(t, mock.failed)
+}
+
+// mockT records failures instead of failing the test
+type mockT struct {
+	testing.TB
+	failed bool
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(string, ...any) {
+	m.failed = true
+}
+
+func (m *mockT) Fatalf(string, ...any) {
+	m.failed = true
+	panic("fatal")
+}
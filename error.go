@@ -1,23 +1,87 @@
 package environ
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrInvalidPort  = errors.New("invalid port")
-	ErrInvalidUrl   = errors.New("invalid url")
-	ErrInvalidEmail = errors.New("invalid email")
-	ErrInvalidBool  = errors.New("invalid boolean value")
-	ErrInvalidInt   = errors.New("invalid int")
-	ErrInvalidFloat = errors.New("invalid float")
-	ErrUnknownType  = errors.New("unknown variable type")
+	ErrInvalidPort     = errors.New("invalid port")
+	ErrInvalidUrl      = errors.New("invalid url")
+	ErrInvalidEmail    = errors.New("invalid email")
+	ErrInvalidBool     = errors.New("invalid boolean value")
+	ErrInvalidInt      = errors.New("invalid int")
+	ErrInvalidFloat    = errors.New("invalid float")
+	ErrInvalidFile     = errors.New("invalid file")
+	ErrInvalidDir      = errors.New("invalid directory")
+	ErrInvalidPath     = errors.New("invalid path")
+	ErrInvalidTime     = errors.New("invalid time")
+	ErrInvalidDate     = errors.New("invalid date")
+	ErrInvalidLocation = errors.New("invalid time location")
+	ErrInvalidDuration = errors.New("invalid duration")
+	ErrInvalidUuid     = errors.New("invalid uuid")
+	ErrInvalidSemver   = errors.New("invalid semantic version")
+	ErrInvalidHex      = errors.New("invalid hexadecimal string")
+	ErrInvalidBase64   = errors.New("invalid base64 string")
+	ErrInvalidUlid     = errors.New("invalid ulid")
+	ErrInvalidJson     = errors.New("invalid json")
+	ErrInvalidEnum     = errors.New("invalid enum value")
+	ErrUnknownType     = errors.New("unknown variable type")
 
 	ErrNotInOneof   = errors.New("the value is not a possible choice")
 	ErrMissingValue = errors.New("missing required variable")
+	ErrExcluded     = errors.New("variable must not be set")
+	ErrCheckFailed  = errors.New("check failed")
+
+	ErrMissingReference = errors.New("missing referenced variable")
+	ErrReferenceCycle   = errors.New("variable references itself")
+	ErrInvalidReference = errors.New("invalid variable reference")
+
+	ErrInvalidConfig   = errors.New("invalid configuration")
+	ErrRestartRequired = errors.New("restart required")
 
 	ErrMissingName     = errors.New("missing variable name")
 	ErrInvalidTag      = errors.New("invalid variable tag")
+	ErrInvalidOption   = errors.New("option doesn't apply to the variable type")
 	ErrSetField        = errors.New("field is not settable")
 	ErrUnsupportedType = errors.New("unsupported variable type")
 
+	ErrUnknownVariable   = errors.New("unknown variable")
+	ErrStrictUnsupported = errors.New("unable to tell unknown variables")
+
 	ErrUnexpected = errors.New("unexpected error")
 )
+
+// VariableError is the error of a variable, along with the variable's name
+type VariableError struct {
+	Name string
+	Err  error
+}
+
+func (e *VariableError) Error() string {
+	return fmt.Sprintf("Err: variable %q. Reason: %v", e.Name, e.Err)
+}
+
+func (e *VariableError) Unwrap() error {
+	return e.Err
+}
+
+// StructError is the error returned by the ValidateEnv or Validate method of a loaded struct.
+// It matches ErrInvalidConfig.
+type StructError struct {
+	// Path is the struct's path from the loaded struct (e.g. "DB.Pool"), or its type name for the loaded struct
+	Path string
+	Err  error
+}
+
+func (e *StructError) Error() string {
+	return fmt.Sprintf("Err: struct %q. Reason: %v: %v", e.Path, ErrInvalidConfig, e.Err)
+}
+
+func (e *StructError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+func (e *StructError) Unwrap() error {
+	return e.Err
+}
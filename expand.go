@@ -0,0 +1,103 @@
+package environ
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// expander expands ${VAR} and ${VAR:-fallback} references
+type expander struct {
+	lookup func(string) (string, bool)
+	// chain is the names of the variables being expanded, outermost first
+	chain []string
+}
+
+// expand replaces the references in the value of the named variable, looking them up in the source
+func expand(source Source, name, value string) (string, error) {
+	e := &expander{lookup: source.Lookup}
+	return e.expandVariable(name, value)
+}
+
+func (e *expander) expandVariable(name, value string) (string, error) {
+	e.chain = append(e.chain, name)
+	defer func() { e.chain = e.chain[:len(e.chain)-1] }()
+
+	return e.expand(value)
+}
+
+func (e *expander) expand(value string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+
+		// "$${" escapes a literal "${"
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1] + "${")
+			value = value[start+2:]
+			continue
+		}
+
+		end := closingBrace(value[start+2:])
+		if end < 0 {
+			return "", fmt.Errorf("%w. unclosed reference in %q", ErrInvalidReference, value)
+		}
+
+		resolved, err := e.resolve(value[start+2 : start+2+end])
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(value[:start] + resolved)
+		value = value[start+2+end+1:]
+	}
+}
+
+// resolve returns the expanded value of a "VAR" or "VAR:-fallback" reference
+func (e *expander) resolve(reference string) (string, error) {
+	name, fallback, hasFallback := strings.Cut(reference, ":-")
+	if name == "" {
+		return "", fmt.Errorf("%w. empty reference in %q", ErrInvalidReference, "${"+reference+"}")
+	}
+
+	if slices.Contains(e.chain, name) {
+		return "", fmt.Errorf("%w. %s", ErrReferenceCycle, e.trace(name))
+	}
+
+	value, exists := e.lookup(name)
+	switch {
+	case hasFallback && value == "":
+		return e.expand(fallback)
+	case !exists:
+		return "", fmt.Errorf("%w. %s", ErrMissingReference, e.trace(name))
+	}
+
+	return e.expandVariable(name, value)
+}
+
+// trace returns the chain of references leading to the named variable (e.g. "URL -> HOST")
+func (e *expander) trace(name string) string {
+	return strings.Join(append(slices.Clone(e.chain), name), " -> ")
+}
+
+// closingBrace returns the index of the brace closing a reference, skipping nested references
+func closingBrace(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
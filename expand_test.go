@@ -0,0 +1,134 @@
+package environ
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{
+		"HOST":   "localhost",
+		"PORT":   "8080",
+		"ADDR":   "${HOST}:${PORT}",
+		"EMPTY":  "",
+		"CYCLE":  "${OTHER}",
+		"OTHER":  "${CYCLE}",
+		"NESTED": "${MISSING}",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		value    string
+		expected string
+		err      error
+		trace    string
+	}{
+		{value: "no references", expected: "no references"},
+		{value: "http://${HOST}:${PORT}", expected: "http://localhost:8080"},
+		{value: "http://${ADDR}/", expected: "http://localhost:8080/"},
+		{value: "${EMPTY}", expected: ""},
+		{value: "${EMPTY:-fallback}", expected: "fallback"},
+		{value: "${MISSING:-fallback}", expected: "fallback"},
+		{value: "${MISSING:-${HOST}}", expected: "localhost"},
+		{value: "$${HOST}", expected: "${HOST}"},
+		{value: "${MISSING}", err: ErrMissingReference, trace: "URL -> MISSING"},
+		{value: "${NESTED}", err: ErrMissingReference, trace: "URL -> NESTED -> MISSING"},
+		{value: "${CYCLE}", err: ErrReferenceCycle, trace: "URL -> CYCLE -> OTHER -> CYCLE"},
+		{value: "${URL}", err: ErrReferenceCycle, trace: "URL -> URL"},
+		{value: "${HOST", err: ErrInvalidReference},
+		{value: "${}", err: ErrInvalidReference},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			e := &expander{lookup: lookup}
+			result, err := e.expandVariable("URL", test.value)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Contains(t, err.Error(), test.trace)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestLoadExpand(t *testing.T) {
+	os.Setenv("TEST_HOST", "example.com")
+	defer os.Unsetenv("TEST_HOST")
+
+	t.Run("expands values and defaults of tagged variables", func(t *testing.T) {
+		type Config struct {
+			Url  string `env:"name=TEST_URL, type=url, expand, default=http://${TEST_HOST}:${TEST_PORT:-8080}"`
+			Name string `env:"name=TEST_NAME, expand"`
+		}
+		os.Setenv("TEST_NAME", "api.${TEST_HOST}")
+		defer os.Unsetenv("TEST_NAME")
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com:8080", result.Url)
+		assert.Equal(t, "api.example.com", result.Name)
+	})
+
+	t.Run("keeps references without expand", func(t *testing.T) {
+		type Config struct {
+			Name string `env:"name=TEST_NAME"`
+		}
+		os.Setenv("TEST_NAME", "api.${TEST_HOST}")
+		defer os.Unsetenv("TEST_NAME")
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, "api.${TEST_HOST}", result.Name)
+	})
+
+	t.Run("expands every variable with WithExpand", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=TEST_PORT, type=port, default=${TEST_DEFAULT_PORT:-3000}"`
+		}
+
+		assert.ErrorIs(t, ValidateSpec[Config](), ErrInvalidTag)
+		assert.NoError(t, ValidateSpec[Config](WithExpand()))
+
+		result, err := Load[Config](WithExpand())
+		assert.NoError(t, err)
+		assert.Equal(t, 3000, result.Port)
+	})
+
+	t.Run("validates the expanded value", func(t *testing.T) {
+		os.Setenv("TEST_PORT", "${TEST_HOST}")
+		defer os.Unsetenv("TEST_PORT")
+
+		_, err := Port("TEST_PORT").Expand().Load()
+		assert.ErrorIs(t, err, ErrInvalidPort)
+	})
+
+	t.Run("returns error with the reference chain", func(t *testing.T) {
+		os.Setenv("TEST_URL", "http://${TEST_ADDR}")
+		os.Setenv("TEST_ADDR", "${TEST_MISSING_HOST}:80")
+		defer os.Unsetenv("TEST_URL")
+		defer os.Unsetenv("TEST_ADDR")
+
+		_, err := Url("TEST_URL").Expand().Load()
+		assert.ErrorIs(t, err, ErrMissingReference)
+		assert.Contains(t, err.Error(), "TEST_URL -> TEST_ADDR -> TEST_MISSING_HOST")
+	})
+
+	t.Run("treats a value expanding to nothing as empty", func(t *testing.T) {
+		os.Setenv("TEST_NAME", "${TEST_UNSET:-}")
+		defer os.Unsetenv("TEST_NAME")
+
+		result, err := String("TEST_NAME").Expand().Default("default").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "default", result)
+	})
+}
@@ -5,12 +5,12 @@ go 1.25.1
 require (
 	github.com/AnatoleLucet/as v0.0.0-20251017165827-c04b6c0b89a2
 	github.com/AnatoleLucet/tiq v0.0.0-20251017170604-d2312d26408f
+	github.com/expr-lang/expr v1.17.6
 	github.com/stretchr/testify v1.11.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/expr-lang/expr v1.17.6 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
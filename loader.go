@@ -0,0 +1,80 @@
+package environ
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Loader loads variables with the options it was created with
+type Loader struct {
+	opts options
+}
+
+// defaultLoader is used by the package-level functions and the variables' Load methods
+var defaultLoader = New()
+
+// Default returns the loader used by the package-level functions and the variables' Load methods.
+// The options given to the package-level functions are applied on top of its options,
+// use With to get a loader with more options.
+func Default() *Loader {
+	return defaultLoader
+}
+
+// New returns a Loader with the given options
+func New(opts ...Option) *Loader {
+	o := options{source: Env, signals: defaultSignals, onDeprecated: logDeprecated}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Loader{opts: o}
+}
+
+// With returns a copy of the loader with more options
+func (l *Loader) With(opts ...Option) *Loader {
+	return &Loader{opts: l.with(opts)}
+}
+
+func (l *Loader) with(opts []Option) options {
+	o := l.opts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Load loads the variables declared on the struct target points to.
+// The struct is left untouched if there is an error.
+func (l *Loader) Load(target any) error {
+	return l.load(target, l.opts)
+}
+
+// LoadInto is like Load but keeps the values already in the struct when their variable is absent.
+// Fields that already have a value are not replaced by the default value of their variable,
+// unless the loader has the WithDefaultsOverride option.
+func (l *Loader) LoadInto(target any) error {
+	opts := l.opts
+	opts.overlay = true
+
+	return l.load(target, opts)
+}
+
+func (l *Loader) load(target any, opts options) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: expected a pointer to a struct, got %T", ErrUnsupportedType, target)
+	}
+
+	loaded := reflect.New(value.Elem().Type()).Elem()
+	if opts.overlay {
+		loaded.Set(value.Elem())
+	}
+
+	if err := loadStruct(loaded, opts); err != nil {
+		return err
+	}
+
+	value.Elem().Set(loaded)
+	return nil
+}
@@ -0,0 +1,157 @@
+package environ
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader(t *testing.T) {
+	type Config struct {
+		Url  string `env:"name=TEST_URL, type=url, expand, default=http://${TEST_HOST}"`
+		Port int    `env:"name=TEST_PORT, type=port, default=8080"`
+	}
+
+	t.Run("loads from its source", func(t *testing.T) {
+		loader := New(WithSource(Map{"TEST_HOST": "example.com", "TEST_PORT": "3000"}))
+
+		var config Config
+		err := loader.Load(&config)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Url: "http://example.com", Port: 3000}, config)
+	})
+
+	t.Run("leaves the struct untouched on error", func(t *testing.T) {
+		loader := New(WithSource(Map{"TEST_HOST": "example.com", "TEST_PORT": "abc"}))
+
+		config := Config{Url: "http://localhost", Port: 80}
+		err := loader.Load(&config)
+		assert.ErrorIs(t, err, ErrInvalidPort)
+		assert.Equal(t, Config{Url: "http://localhost", Port: 80}, config)
+	})
+
+	t.Run("returns error for non struct pointers", func(t *testing.T) {
+		var config Config
+		assert.ErrorIs(t, New().Load(config), ErrUnsupportedType)
+		assert.ErrorIs(t, New().Load((*Config)(nil)), ErrUnsupportedType)
+
+		var port int
+		assert.ErrorIs(t, New().Load(&port), ErrUnsupportedType)
+	})
+
+	t.Run("adds options with With", func(t *testing.T) {
+		loader := New(WithSource(Map{"TEST_NAME": "${TEST_HOST}", "TEST_HOST": "example.com"}))
+
+		result, err := String("TEST_NAME").LoadFrom(loader)
+		assert.NoError(t, err)
+		assert.Equal(t, "${TEST_HOST}", result)
+
+		result, err = String("TEST_NAME").LoadFrom(loader.With(WithExpand()))
+		assert.NoError(t, err)
+		assert.Equal(t, "example.com", result)
+	})
+
+	t.Run("loads builders from the loader", func(t *testing.T) {
+		loader := New(WithSource(SourceFunc(func(name string) (string, bool) {
+			return "9090", name == "TEST_PORT"
+		})))
+
+		result, err := Port("TEST_PORT").LoadFrom(loader)
+		assert.NoError(t, err)
+		assert.Equal(t, 9090, result)
+
+		_, err = Port("TEST_OTHER_PORT").LoadFrom(loader)
+		assert.ErrorIs(t, err, ErrMissingValue)
+	})
+
+	t.Run("package functions use the default loader", func(t *testing.T) {
+		os.Setenv("TEST_HOST", "example.com")
+		os.Setenv("TEST_PORT", "4000")
+		defer os.Unsetenv("TEST_HOST")
+		defer os.Unsetenv("TEST_PORT")
+
+		var config Config
+		assert.NoError(t, Default().Load(&config))
+		assert.Equal(t, Config{Url: "http://example.com", Port: 4000}, config)
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, config, result)
+
+		port, err := Port("TEST_PORT").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, 4000, port)
+	})
+
+	t.Run("With leaves the default loader unchanged", func(t *testing.T) {
+		os.Setenv("TEST_PORT", "4000")
+		defer os.Unsetenv("TEST_PORT")
+
+		port, err := Port("TEST_PORT").LoadFrom(Default().With(WithSource(Map{"TEST_PORT": "3000"})))
+		assert.NoError(t, err)
+		assert.Equal(t, 3000, port)
+
+		port, err = Port("TEST_PORT").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, 4000, port)
+	})
+}
+
+func TestLoadInto(t *testing.T) {
+	type Config struct {
+		Host    string   `env:"name=TEST_HOST"`
+		Port    int      `env:"name=TEST_PORT, type=port, default=8080"`
+		Level   string   `env:"name=TEST_LOG_LEVEL, default=info"`
+		Workers *int     `env:"name=TEST_WORKERS, type=int, optional"`
+		Tags    []string `env:"name=TEST_TAGS, type=json, optional"`
+	}
+
+	workers := 4
+	fromFile := func() Config {
+		return Config{Host: "file.example.com", Port: 3000, Workers: &workers}
+	}
+
+	t.Run("keeps fields of absent variables", func(t *testing.T) {
+		config := fromFile()
+		err := New(WithSource(Map{"TEST_TAGS": `["a"]`})).LoadInto(&config)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Host: "file.example.com", Port: 3000, Level: "info", Workers: &workers, Tags: []string{"a"}}, config)
+	})
+
+	t.Run("overrides fields of set variables", func(t *testing.T) {
+		config := fromFile()
+		err := New(WithSource(Map{"TEST_HOST": "env.example.com", "TEST_PORT": "9090", "TEST_WORKERS": "8"})).LoadInto(&config)
+		assert.NoError(t, err)
+		assert.Equal(t, "env.example.com", config.Host)
+		assert.Equal(t, 9090, config.Port)
+		assert.Equal(t, 8, *config.Workers)
+		assert.Equal(t, 4, workers)
+	})
+
+	t.Run("overrides with defaults when asked", func(t *testing.T) {
+		config := fromFile()
+		err := New(WithSource(Map{}), WithDefaultsOverride()).LoadInto(&config)
+		assert.NoError(t, err)
+		assert.Equal(t, "file.example.com", config.Host)
+		assert.Equal(t, 8080, config.Port)
+	})
+
+	t.Run("returns error for missing variables of empty fields", func(t *testing.T) {
+		config := Config{}
+		err := New(WithSource(Map{})).LoadInto(&config)
+		assert.ErrorIs(t, err, ErrMissingValue)
+		assert.Equal(t, Config{}, config)
+	})
+
+	t.Run("uses the default loader", func(t *testing.T) {
+		os.Setenv("TEST_PORT", "4000")
+		defer os.Unsetenv("TEST_PORT")
+
+		config := fromFile()
+		err := LoadInto(&config)
+		assert.NoError(t, err)
+		assert.Equal(t, 4000, config.Port)
+		assert.Equal(t, "file.example.com", config.Host)
+	})
+}
@@ -0,0 +1,64 @@
+package environ
+
+import "reflect"
+
+// Presence tells how a variable was found in the environment
+type Presence int
+
+const (
+	// Absent means the variable is not defined
+	Absent Presence = iota
+	// Empty means the variable is defined to an empty string
+	Empty
+	// Present means the variable is defined to a non-empty value
+	Present
+)
+
+func (p Presence) String() string {
+	switch p {
+	case Empty:
+		return "empty"
+	case Present:
+		return "present"
+	}
+
+	return "absent"
+}
+
+// Maybe holds a loaded value along with how its variable was found.
+// Struct fields of type Maybe[T] are loaded as optional variables of type T.
+type Maybe[T any] struct {
+	Value    T
+	Presence Presence
+	// Name is the name the variable was found under (see Alias and Deprecated)
+	Name string
+}
+
+// IsSet reports whether the variable is defined, even to an empty string
+func (m Maybe[T]) IsSet() bool {
+	return m.Presence != Absent
+}
+
+// IsEmpty reports whether the variable is defined to an empty string
+func (m Maybe[T]) IsEmpty() bool {
+	return m.Presence == Empty
+}
+
+// Get returns the value and whether the variable is defined
+func (m Maybe[T]) Get() (T, bool) {
+	return m.Value, m.IsSet()
+}
+
+func (m Maybe[T]) isMaybe() {}
+
+var maybeType = reflect.TypeFor[interface{ isMaybe() }]()
+
+// maybeValueType returns T if typ is a Maybe[T]
+func maybeValueType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || !typ.Implements(maybeType) {
+		return nil, false
+	}
+
+	field, _ := typ.FieldByName("Value")
+	return field.Type, true
+}
@@ -0,0 +1,88 @@
+package environ
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaybe(t *testing.T) {
+	t.Run("reports absent variable", func(t *testing.T) {
+		m := Maybe[string]{}
+		value, ok := m.Get()
+		assert.False(t, ok)
+		assert.False(t, m.IsSet())
+		assert.False(t, m.IsEmpty())
+		assert.Equal(t, "", value)
+	})
+
+	t.Run("reports empty variable", func(t *testing.T) {
+		m := Maybe[string]{Presence: Empty}
+		assert.True(t, m.IsSet())
+		assert.True(t, m.IsEmpty())
+	})
+
+	t.Run("reports present variable", func(t *testing.T) {
+		m := Maybe[int]{Value: 3, Presence: Present}
+		value, ok := m.Get()
+		assert.True(t, ok)
+		assert.False(t, m.IsEmpty())
+		assert.Equal(t, 3, value)
+	})
+}
+
+func TestLoadMaybe(t *testing.T) {
+	t.Run("loads maybe fields", func(t *testing.T) {
+		type Config struct {
+			Prefix Maybe[string] `env:"name=PROXY_PREFIX, allow_empty"`
+			Limit  Maybe[int]    `env:"name=RATE_LIMIT, type=int"`
+			Region Maybe[string] `env:"name=REGION, default=eu"`
+		}
+		os.Setenv("PROXY_PREFIX", "")
+		os.Setenv("RATE_LIMIT", "0")
+		os.Unsetenv("REGION")
+		defer func() {
+			os.Unsetenv("PROXY_PREFIX")
+			os.Unsetenv("RATE_LIMIT")
+		}()
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.Equal(t, Maybe[string]{Value: "", Presence: Empty, Name: "PROXY_PREFIX"}, result.Prefix)
+		assert.Equal(t, Maybe[int]{Value: 0, Presence: Present, Name: "RATE_LIMIT"}, result.Limit)
+		assert.Equal(t, Maybe[string]{Value: "eu", Presence: Absent}, result.Region)
+	})
+
+	t.Run("does not require maybe fields", func(t *testing.T) {
+		type Config struct {
+			Limit Maybe[int] `env:"name=RATE_LIMIT, type=int"`
+		}
+		os.Unsetenv("RATE_LIMIT")
+
+		result, err := Load[Config]()
+		assert.NoError(t, err)
+		assert.False(t, result.Limit.IsSet())
+	})
+
+	t.Run("validates maybe fields", func(t *testing.T) {
+		type Config struct {
+			Limit Maybe[int] `env:"name=RATE_LIMIT, type=int"`
+		}
+		os.Setenv("RATE_LIMIT", "lots")
+		defer os.Unsetenv("RATE_LIMIT")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrInvalidInt)
+	})
+
+	t.Run("loads maybe with builder", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := String("TEST_VAR").AllowEmpty().Default("/api").LoadMaybe()
+		assert.NoError(t, err)
+		assert.True(t, result.IsEmpty())
+		assert.Equal(t, "", result.Value)
+	})
+}
@@ -0,0 +1,151 @@
+package environ
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/AnatoleLucet/tiq"
+)
+
+// NamingMode tells which fields without a name get one from their path
+type NamingMode int
+
+const (
+	// NamingOff only loads the fields with a name in their tag
+	NamingOff NamingMode = iota
+	// NamingTagged names the fields with an env tag but no name
+	NamingTagged
+	// NamingAuto names every exported field without a name, tagged or not
+	NamingAuto
+)
+
+// NamingStrategy returns the name of the variable of a field from its path (e.g. ["DB", "MaxOpenConns"])
+type NamingStrategy func(path []string) string
+
+// ScreamingSnake names the variable from the whole path of the field (DB.MaxOpenConns is DB_MAX_OPEN_CONNS)
+func ScreamingSnake(path []string) string {
+	words := make([]string, len(path))
+	for i, name := range path {
+		words[i] = screamingSnake(name)
+	}
+
+	return strings.Join(words, "_")
+}
+
+// ScreamingSnakeLeaf names the variable from the field's own name (DB.MaxOpenConns is MAX_OPEN_CONNS).
+// It's meant to be used with prefixes on the nested structs.
+func ScreamingSnakeLeaf(path []string) string {
+	return screamingSnake(path[len(path)-1])
+}
+
+// screamingSnake converts a Go name to SCREAMING_SNAKE_CASE (e.g. TLSCertFile is TLS_CERT_FILE)
+func screamingSnake(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// a new word starts after a lowercase letter or digit, or at the last capital of an acronym
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// WithNaming will name the variables of the fields without a name from their path, using the strategy
+// (ScreamingSnake if nil). The type of the variable is then guessed from the field's type when the tag doesn't give one.
+func WithNaming(mode NamingMode, strategy NamingStrategy) Option {
+	if strategy == nil {
+		strategy = ScreamingSnake
+	}
+
+	return func(o *options) {
+		o.naming, o.namingStrategy = mode, strategy
+	}
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	locationType = reflect.TypeFor[time.Location]()
+)
+
+// namedFromPath reports whether the field's variable should be named from the field's path
+func namedFromPath(field *tiq.Field, variable *Variable[any], opts options) bool {
+	tag, tagged := field.Tag("env")
+	switch {
+	case opts.naming == NamingOff, !field.IsExported(), tag == "-":
+		return false
+	case opts.naming == NamingTagged && !tagged:
+		return false
+	case variable.Type != "":
+		return true
+	}
+
+	target := variable.target
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	// other structs are nested structs, whose own fields are variables
+	return target.Kind() != reflect.Struct || target == timeType || target == urlType || target == locationType
+}
+
+// guessType returns the variable type matching a Go type
+func guessType(t reflect.Type) VariableType {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case durationType:
+		return TypeDuration
+	case timeType:
+		return TypeTime
+	case urlType:
+		return TypeUrl
+	case locationType:
+		return TypeLocation
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	case reflect.Slice, reflect.Map:
+		return TypeJson
+	}
+
+	return ""
+}
+
+// checkCollisions returns an error if two fields declare the same variable name
+func checkCollisions(fields []structField) error {
+	declared := map[string]string{}
+	for _, field := range fields {
+		for _, name := range field.Variable.names() {
+			if other, ok := declared[name]; ok && other != field.Path {
+				return fmt.Errorf("%w: variable %q is declared by fields %q and %q", ErrInvalidTag, name, other, field.Path)
+			}
+
+			declared[name] = field.Path
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package environ
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreamingSnake(t *testing.T) {
+	tests := []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{"Port"}, "PORT"},
+		{[]string{"MaxOpenConns"}, "MAX_OPEN_CONNS"},
+		{[]string{"URL"}, "URL"},
+		{[]string{"DBHost"}, "DB_HOST"},
+		{[]string{"TLSCertFile"}, "TLS_CERT_FILE"},
+		{[]string{"UserID"}, "USER_ID"},
+		{[]string{"HTTP2Enabled"}, "HTTP2_ENABLED"},
+		{[]string{"Retry3Times"}, "RETRY3_TIMES"},
+		{[]string{"DB", "MaxOpenConns"}, "DB_MAX_OPEN_CONNS"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, ScreamingSnake(test.path))
+		})
+	}
+
+	t.Run("leaf", func(t *testing.T) {
+		assert.Equal(t, "MAX_OPEN_CONNS", ScreamingSnakeLeaf([]string{"DB", "MaxOpenConns"}))
+	})
+}
+
+type testNamingDB struct {
+	Host         string `env:"default=localhost"`
+	MaxOpenConns int    `env:"optional"`
+	Timeout      time.Duration
+}
+
+type testNamingConfig struct {
+	Port     int      `env:"type=port"`
+	Url      *url.URL `env:"optional"`
+	Debug    bool
+	Tags     []string
+	Renamed  string `env:"name=APP_NAME, optional"`
+	DB       testNamingDB
+	Skipped  string `env:"-"`
+	internal string
+}
+
+func TestLoadNaming(t *testing.T) {
+	t.Run("names tagged fields from their path", func(t *testing.T) {
+		source := Map{"PORT": "8080", "DB_MAX_OPEN_CONNS": "10", "DEBUG": "true", "APP_NAME": "app"}
+
+		result, err := Load[testNamingConfig](WithSource(source), WithNaming(NamingTagged, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, result.Port)
+		assert.Nil(t, result.Url)
+		assert.False(t, result.Debug)
+		assert.Equal(t, "app", result.Renamed)
+		assert.Equal(t, testNamingDB{Host: "localhost", MaxOpenConns: 10}, result.DB)
+	})
+
+	t.Run("names every exported field in auto mode", func(t *testing.T) {
+		source := Map{
+			"PORT":       "8080",
+			"URL":        "https://example.com",
+			"DEBUG":      "true",
+			"TAGS":       `["a","b"]`,
+			"DB_TIMEOUT": "5s",
+			"SKIPPED":    "skipped",
+			"INTERNAL":   "internal",
+		}
+
+		result, err := Load[testNamingConfig](WithSource(source), WithNaming(NamingAuto, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "example.com", result.Url.Host)
+		assert.True(t, result.Debug)
+		assert.Equal(t, []string{"a", "b"}, result.Tags)
+		assert.Equal(t, 5*time.Second, result.DB.Timeout)
+		assert.Empty(t, result.Skipped)
+		assert.Empty(t, result.internal)
+	})
+
+	t.Run("validates the guessed type", func(t *testing.T) {
+		source := Map{"PORT": "8080", "DEBUG": "maybe", "TAGS": "[]", "DB_TIMEOUT": "5s"}
+
+		_, err := Load[testNamingConfig](WithSource(source), WithNaming(NamingAuto, nil))
+		assert.ErrorIs(t, err, ErrInvalidBool)
+		assert.ErrorContains(t, err, `"DEBUG"`)
+	})
+
+	t.Run("uses the naming strategy", func(t *testing.T) {
+		type Config struct {
+			DB testNamingDB `env:"prefix=DATABASE_"`
+		}
+
+		result, err := Describe[Config](WithNaming(NamingTagged, ScreamingSnakeLeaf))
+		assert.NoError(t, err)
+		assert.Equal(t, "DATABASE_HOST", result[0].Name)
+		assert.Equal(t, "DATABASE_MAX_OPEN_CONNS", result[1].Name)
+	})
+
+	t.Run("returns error on collision", func(t *testing.T) {
+		type Config struct {
+			DBHost string `env:"optional"`
+			DB     struct {
+				Host string `env:"optional"`
+			}
+		}
+
+		err := ValidateSpec[Config](WithNaming(NamingTagged, nil))
+		assert.ErrorIs(t, err, ErrInvalidTag)
+		assert.ErrorContains(t, err, `variable "DB_HOST" is declared by fields "DBHost" and "DB.Host"`)
+	})
+
+	t.Run("returns error on collision with aliases", func(t *testing.T) {
+		type Config struct {
+			Url    string `env:"name=APP_URL|URL"`
+			Legacy string `env:"name=URL"`
+		}
+
+		assert.ErrorIs(t, ValidateSpec[Config](), ErrInvalidTag)
+	})
+}
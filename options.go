@@ -0,0 +1,116 @@
+package environ
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// Option configures how a struct's variables are loaded and watched
+type Option func(*options)
+
+type options struct {
+	source Source
+	prefix string
+	expand bool
+	strict bool
+
+	// onDeprecated is called when a variable is found under one of its deprecated names
+	onDeprecated func(deprecated, name string)
+
+	naming         NamingMode
+	namingStrategy NamingStrategy
+	dialect        Dialect
+
+	// overlay is set by LoadInto to keep the values already in the struct
+	overlay          bool
+	overrideDefaults bool
+
+	// signals, interval and files trigger the reloads of Watch
+	signals  []os.Signal
+	interval time.Duration
+	files    []string
+}
+
+var defaultSignals = []os.Signal{syscall.SIGHUP}
+
+// logDeprecated is the default deprecation handler, logging a warning with slog
+func logDeprecated(deprecated, name string) {
+	slog.Warn("environment variable is deprecated", "variable", deprecated, "use", name)
+}
+
+// newOptions returns the options of the default loader with opts applied
+func newOptions(opts []Option) options {
+	return defaultLoader.with(opts)
+}
+
+// WithSource sets where the variables are looked up (the process environment by default)
+func WithSource(source Source) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// WithPrefix will prepend the prefix to the names of every variable (e.g. "BILLING_")
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix += prefix
+	}
+}
+
+// WithExpand will expand ${VAR} references in the values and defaults of every variable,
+// as if they were all tagged with expand
+func WithExpand() Option {
+	return func(o *options) {
+		o.expand = true
+	}
+}
+
+// WithStrict will reject the set variables starting with the prefix of the configuration
+// that no field declares nor references (e.g. a misspelled APP_PORTT).
+// It needs a prefix (WithPrefix or an EnvPrefix method) and a source that is a Lister.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithDeprecationHandler sets the function called when a variable is found under one of its deprecated names,
+// with the deprecated name and the variable's name. By default a warning is logged with slog.
+func WithDeprecationHandler(handler func(deprecated, name string)) Option {
+	return func(o *options) {
+		o.onDeprecated = handler
+	}
+}
+
+// WithDefaultsOverride will make LoadInto set the default value of absent variables
+// on fields that already have a value, instead of keeping it
+func WithDefaultsOverride() Option {
+	return func(o *options) {
+		o.overrideDefaults = true
+	}
+}
+
+// WithSignals sets the signals making Watch reload the configuration (SIGHUP by default).
+// Calling it without any signal disables signal reloads.
+func WithSignals(signals ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = signals
+	}
+}
+
+// WithInterval will make Watch reload the configuration at every interval
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.interval = interval
+	}
+}
+
+// WithFiles will make Watch reload the configuration when one of the files changes
+func WithFiles(paths ...string) Option {
+	return func(o *options) {
+		o.files = append(slices.Clip(o.files), paths...)
+	}
+}
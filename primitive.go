@@ -1,5 +1,10 @@
 package environ
 
+import (
+	"net/url"
+	"time"
+)
+
 // String will ensure the variable is a string
 func String(name string) VariableBuilder[string] {
 	return VariableBuilder[string]{
@@ -42,9 +47,114 @@ func Url(name string) VariableBuilder[string] {
 	}
 }
 
+// ParsedUrl is like Url but returns the parsed *url.URL
+func ParsedUrl(name string) VariableBuilder[*url.URL] {
+	return VariableBuilder[*url.URL]{
+		Variable: Variable[*url.URL]{Name: name, Type: TypeUrl},
+	}
+}
+
 // Email will ensure the variable is a valid email address
 func Email(name string) VariableBuilder[string] {
 	return VariableBuilder[string]{
 		Variable: Variable[string]{Name: name, Type: TypeEmail},
 	}
 }
+
+// File will ensure the variable is a path to an existing regular file
+func File(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeFile},
+	}
+}
+
+// Dir will ensure the variable is a path to an existing directory
+func Dir(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeDir},
+	}
+}
+
+// Path will ensure the variable is a filesystem path ("~" is expanded to the home directory)
+func Path(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypePath},
+	}
+}
+
+// Time will ensure the variable is a time (RFC 3339 unless a Layout is set)
+func Time(name string) VariableBuilder[time.Time] {
+	return VariableBuilder[time.Time]{
+		Variable: Variable[time.Time]{Name: name, Type: TypeTime},
+	}
+}
+
+// Date will ensure the variable is a date (YYYY-MM-DD unless a Layout is set)
+func Date(name string) VariableBuilder[time.Time] {
+	return VariableBuilder[time.Time]{
+		Variable: Variable[time.Time]{Name: name, Type: TypeDate},
+	}
+}
+
+// Duration will ensure the variable is a duration (e.g. "300ms", "1h30m")
+func Duration(name string) VariableBuilder[time.Duration] {
+	return VariableBuilder[time.Duration]{
+		Variable: Variable[time.Duration]{Name: name, Type: TypeDuration},
+	}
+}
+
+// Location will ensure the variable is a valid IANA time zone (e.g. "Europe/Paris")
+func Location(name string) VariableBuilder[*time.Location] {
+	return VariableBuilder[*time.Location]{
+		Variable: Variable[*time.Location]{Name: name, Type: TypeLocation},
+	}
+}
+
+// Uuid will ensure the variable is a UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)
+func Uuid(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeUuid},
+	}
+}
+
+// Semver will ensure the variable is a semantic version (e.g. "1.2.3", "v2.0.0-rc.1")
+func Semver(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeSemver},
+	}
+}
+
+// Hex will ensure the variable is a hexadecimal string
+func Hex(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeHex},
+	}
+}
+
+// Base64 will ensure the variable is base64 encoded and return the decoded data
+func Base64(name string) VariableBuilder[[]byte] {
+	return VariableBuilder[[]byte]{
+		Variable: Variable[[]byte]{Name: name, Type: TypeBase64},
+	}
+}
+
+// Ulid will ensure the variable is a ULID
+func Ulid(name string) VariableBuilder[string] {
+	return VariableBuilder[string]{
+		Variable: Variable[string]{Name: name, Type: TypeUlid},
+	}
+}
+
+// JSON will decode the variable as JSON into T (struct, map, slice...)
+func JSON[T any](name string) VariableBuilder[T] {
+	return VariableBuilder[T]{
+		Variable: Variable[T]{Name: name, Type: TypeJson},
+	}
+}
+
+// Enum will ensure the variable is one of T's values (see RegisterEnum)
+func Enum[T any](name string) VariableBuilder[T] {
+	return VariableBuilder[T]{
+		Variable: Variable[T]{Name: name, Type: TypeEnum},
+	}
+}
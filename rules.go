@@ -0,0 +1,142 @@
+package environ
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// loadedVariable is a variable along with what was loaded for it
+type loadedVariable struct {
+	variable *Variable[any]
+	value    Maybe[any]
+}
+
+// isSet reports whether the variable was given a value in the environment
+func (l loadedVariable) isSet() bool {
+	return l.value.Presence == Present || (l.value.Presence == Empty && l.variable.AllowEmpty)
+}
+
+// condition is a "NAME=value1|value2" rule
+type condition struct {
+	name   string
+	values []string
+}
+
+func parseCondition(rule string) condition {
+	name, values, _ := strings.Cut(rule, "=")
+
+	c := condition{name: strings.TrimSpace(name)}
+	for value := range strings.SplitSeq(values, "|") {
+		c.values = append(c.values, strings.TrimSpace(value))
+	}
+
+	return c
+}
+
+func (c condition) String() string {
+	return fmt.Sprintf("%s=%s", c.name, strings.Join(c.values, "|"))
+}
+
+// matches reports whether the loaded variable equals one of the condition's values
+func (c condition) matches(trigger loadedVariable) bool {
+	if trigger.value.Value == nil {
+		return false
+	}
+
+	for _, raw := range c.values {
+		expected, err := validateTypeWith[any](trigger.variable.Type, raw, trigger.variable.typeOptions())
+		if err == nil && reflect.DeepEqual(expected, trigger.value.Value) {
+			return true
+		}
+
+		if strings.EqualFold(raw, fmt.Sprint(trigger.value.Value)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateRules checks that the rules of every variable refer to declared variables
+func validateRules(variables []*Variable[any]) error {
+	declared := map[string]bool{}
+	for _, variable := range variables {
+		declared[variable.Name] = true
+	}
+
+	for _, variable := range variables {
+		for _, name := range variable.ruleTriggers() {
+			if !declared[name] {
+				return fmt.Errorf("%w for variable %q: rule refers to unknown variable %q", ErrInvalidTag, variable.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleTriggers returns the names of the variables the rules depend on
+func (v Variable[T]) ruleTriggers() []string {
+	var names []string
+	if v.RequiredIf != "" {
+		names = append(names, parseCondition(v.RequiredIf).name)
+	}
+	if v.RequiredUnless != "" {
+		names = append(names, parseCondition(v.RequiredUnless).name)
+	}
+
+	names = append(names, v.RequiredWith...)
+	return append(names, v.ExcludedWith...)
+}
+
+// checkRules evaluates the conditional requirements between the loaded variables
+func checkRules(loaded []loadedVariable) error {
+	variables := make([]*Variable[any], 0, len(loaded))
+	byName := map[string]loadedVariable{}
+	for _, l := range loaded {
+		variables = append(variables, l.variable)
+		byName[l.variable.Name] = l
+	}
+
+	if err := validateRules(variables); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, l := range loaded {
+		v := l.variable
+		satisfied := l.isSet() || v.Default != nil
+
+		if v.RequiredIf != "" {
+			c := parseCondition(v.RequiredIf)
+			if !satisfied && c.matches(byName[c.name]) {
+				errs = append(errs, &VariableError{v.Name, fmt.Errorf("%w when %s", ErrMissingValue, c)})
+			}
+		}
+
+		if v.RequiredUnless != "" {
+			c := parseCondition(v.RequiredUnless)
+			if !satisfied && !c.matches(byName[c.name]) {
+				errs = append(errs, &VariableError{v.Name, fmt.Errorf("%w unless %s", ErrMissingValue, c)})
+			}
+		}
+
+		for _, name := range v.RequiredWith {
+			if !satisfied && byName[name].isSet() {
+				errs = append(errs, &VariableError{v.Name, fmt.Errorf("%w when %q is set", ErrMissingValue, name)})
+				break
+			}
+		}
+
+		for _, name := range v.ExcludedWith {
+			if l.isSet() && byName[name].isSet() {
+				errs = append(errs, &VariableError{v.Name, fmt.Errorf("%w when %q is set", ErrExcluded, name)})
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
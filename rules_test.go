@@ -0,0 +1,105 @@
+package environ
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCondition(t *testing.T) {
+	c := parseCondition("STORAGE = s3|gcs")
+	assert.Equal(t, condition{name: "STORAGE", values: []string{"s3", "gcs"}}, c)
+	assert.Equal(t, "STORAGE=s3|gcs", c.String())
+}
+
+func TestLoadRules(t *testing.T) {
+	type TLSConfig struct {
+		Enabled bool   `env:"name=TLS_ENABLED, type=bool, default=false"`
+		Cert    string `env:"name=TLS_CERT, required_if=TLS_ENABLED=true"`
+	}
+
+	t.Run("requires variable when condition matches", func(t *testing.T) {
+		os.Setenv("TLS_ENABLED", "on")
+		os.Unsetenv("TLS_CERT")
+		defer os.Unsetenv("TLS_ENABLED")
+
+		_, err := Load[TLSConfig]()
+		assert.ErrorIs(t, err, ErrMissingValue)
+		assert.Contains(t, err.Error(), "TLS_CERT")
+		assert.Contains(t, err.Error(), "TLS_ENABLED=true")
+	})
+
+	t.Run("does not require variable when condition doesn't match", func(t *testing.T) {
+		os.Unsetenv("TLS_ENABLED")
+		os.Unsetenv("TLS_CERT")
+
+		result, err := Load[TLSConfig]()
+		assert.NoError(t, err)
+		assert.Equal(t, "", result.Cert)
+	})
+
+	t.Run("evaluates required_unless", func(t *testing.T) {
+		type Config struct {
+			Storage string `env:"name=STORAGE, oneof=s3|local"`
+			Dir     string `env:"name=STORAGE_DIR, required_unless=STORAGE=s3"`
+		}
+		os.Setenv("STORAGE", "local")
+		os.Unsetenv("STORAGE_DIR")
+		defer os.Unsetenv("STORAGE")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrMissingValue)
+		assert.Contains(t, err.Error(), "STORAGE_DIR")
+
+		os.Setenv("STORAGE", "s3")
+		_, err = Load[Config]()
+		assert.NoError(t, err)
+	})
+
+	t.Run("evaluates required_with", func(t *testing.T) {
+		type Config struct {
+			User     string `env:"name=SMTP_USER, optional"`
+			Password string `env:"name=SMTP_PASSWORD, required_with=SMTP_USER"`
+		}
+		os.Setenv("SMTP_USER", "mailer")
+		os.Unsetenv("SMTP_PASSWORD")
+		defer os.Unsetenv("SMTP_USER")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrMissingValue)
+		assert.Contains(t, err.Error(), "SMTP_PASSWORD")
+		assert.Contains(t, err.Error(), "SMTP_USER")
+
+		os.Unsetenv("SMTP_USER")
+		_, err = Load[Config]()
+		assert.NoError(t, err)
+	})
+
+	t.Run("evaluates excluded_with", func(t *testing.T) {
+		type Config struct {
+			Token   string `env:"name=API_TOKEN, optional, excluded_with=API_KEY_FILE"`
+			KeyFile string `env:"name=API_KEY_FILE, optional"`
+		}
+		os.Setenv("API_TOKEN", "t0k3n")
+		os.Setenv("API_KEY_FILE", "/run/secrets/key")
+		defer os.Unsetenv("API_TOKEN")
+		defer os.Unsetenv("API_KEY_FILE")
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrExcluded)
+		assert.Contains(t, err.Error(), "API_TOKEN")
+		assert.Contains(t, err.Error(), "API_KEY_FILE")
+	})
+
+	t.Run("returns tag error for unknown variable", func(t *testing.T) {
+		type Config struct {
+			Bucket string `env:"name=S3_BUCKET, required_if=STORAGE=s3"`
+		}
+
+		assert.ErrorIs(t, ValidateSpec[Config](), ErrInvalidTag)
+
+		_, err := Load[Config]()
+		assert.ErrorIs(t, err, ErrInvalidTag)
+	})
+}
@@ -0,0 +1,70 @@
+package environ
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Source is where the variables are looked up
+type Source interface {
+	// Lookup returns the value of the variable and whether it's set
+	Lookup(name string) (string, bool)
+}
+
+// Lister is implemented by the sources that can list their variables (see WithStrict)
+type Lister interface {
+	// Names returns the names of every variable set in the source
+	Names() []string
+}
+
+// SourceFunc is a function used as a Source
+type SourceFunc func(name string) (string, bool)
+
+func (f SourceFunc) Lookup(name string) (string, bool) {
+	return f(name)
+}
+
+// Map is a Source reading the variables from a map
+type Map map[string]string
+
+func (m Map) Lookup(name string) (string, bool) {
+	value, ok := m[name]
+	return value, ok
+}
+
+func (m Map) Names() []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Env is the Source reading the variables from the process environment
+var Env Source = envSource{}
+
+type envSource struct{}
+
+func (envSource) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (envSource) Names() []string {
+	var names []string
+	for _, variable := range os.Environ() {
+		if name, _, _ := strings.Cut(variable, "="); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// recorder is a Source recording the names looked up in its source
+type recorder struct {
+	Source
+	names map[string]bool
+}
+
+func (r *recorder) Lookup(name string) (string, bool) {
+	r.names[name] = true
+	return r.Source.Lookup(name)
+}
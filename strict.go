@@ -0,0 +1,36 @@
+package environ
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// checkUnknown returns an error for every variable of the source starting with the prefix
+// that isn't one of the names of the fields' variables, and wasn't looked up while loading them
+func checkUnknown(source Source, prefix string, fields []structField, lookedUp map[string]bool) error {
+	if prefix == "" {
+		return fmt.Errorf("%w: no prefix to tell which variables belong to the configuration", ErrStrictUnsupported)
+	}
+
+	lister, ok := source.(Lister)
+	if !ok {
+		return fmt.Errorf("%w: the source can't list its variables", ErrStrictUnsupported)
+	}
+
+	known := map[string]bool{}
+	for _, field := range fields {
+		for _, name := range field.Variable.names() {
+			known[name] = true
+		}
+	}
+
+	var errs []error
+	for _, name := range lister.Names() {
+		if strings.HasPrefix(name, prefix) && !known[name] && !lookedUp[name] {
+			errs = append(errs, &VariableError{name, ErrUnknownVariable})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package environ
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type strictConfig struct {
+	Url  string `env:"name=URL, type=url, expand, default=http://${TEST_HOST:-localhost}"`
+	Port int    `env:"name=PORT, type=port, default=8080"`
+	Name string `env:"name=NAME|SERVICE, deprecated=APP, optional"`
+}
+
+func (strictConfig) EnvPrefix() string {
+	return "TEST_"
+}
+
+func TestLoadStrict(t *testing.T) {
+	t.Run("accepts declared and referenced variables", func(t *testing.T) {
+		source := Map{"TEST_HOST": "example.com", "TEST_PORT": "3000", "TEST_NAME": "api", "TEST_APP": "old", "OTHER": "x"}
+
+		result, err := Load[strictConfig](WithSource(source), WithStrict())
+		assert.NoError(t, err)
+		assert.Equal(t, strictConfig{Url: "http://example.com", Port: 3000, Name: "api"}, result)
+	})
+
+	t.Run("returns error for unknown variables with the prefix", func(t *testing.T) {
+		source := Map{"TEST_PORTT": "3000", "TEST_LEVEL": "debug", "OTHER": "x"}
+
+		_, err := Load[strictConfig](WithSource(source), WithStrict())
+		assert.ErrorIs(t, err, ErrUnknownVariable)
+		assert.Contains(t, err.Error(), `"TEST_PORTT"`)
+		assert.Contains(t, err.Error(), `"TEST_LEVEL"`)
+		assert.NotContains(t, err.Error(), `"OTHER"`)
+
+		_, err = Load[strictConfig](WithSource(source))
+		assert.NoError(t, err)
+	})
+
+	t.Run("uses the loader prefix", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=PORT, type=port"`
+		}
+		source := Map{"APP_PORT": "3000", "APP_DEBUG": "true"}
+
+		err := New(WithSource(source), WithPrefix("APP_"), WithStrict()).Load(&Config{})
+		assert.ErrorIs(t, err, ErrUnknownVariable)
+		assert.Contains(t, err.Error(), `"APP_DEBUG"`)
+	})
+
+	t.Run("reads the process environment", func(t *testing.T) {
+		t.Setenv("TEST_PORTT", "3000")
+
+		_, err := Load[strictConfig](WithStrict())
+		assert.ErrorIs(t, err, ErrUnknownVariable)
+	})
+
+	t.Run("returns error without prefix", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=PORT, type=port, default=8080"`
+		}
+
+		_, err := Load[Config](WithSource(Map{}), WithStrict())
+		assert.ErrorIs(t, err, ErrStrictUnsupported)
+	})
+
+	t.Run("returns error for sources that can't list their variables", func(t *testing.T) {
+		source := SourceFunc(func(string) (string, bool) { return "", false })
+
+		_, err := Load[strictConfig](WithSource(source), WithStrict())
+		assert.ErrorIs(t, err, ErrStrictUnsupported)
+	})
+}
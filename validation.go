@@ -1,10 +1,19 @@
 package environ
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/mail"
 	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/AnatoleLucet/as"
 )
@@ -49,17 +58,57 @@ func validatePort(v string) (int, error) {
 	return port, nil
 }
 
+// typeOptions holds the settings some types need on top of the raw value
+type typeOptions struct {
+	Schemes     []string
+	RequirePath bool
+	NoUserinfo  bool
+
+	Exists   bool
+	Readable bool
+	Writable bool
+	Abs      bool
+	// Lexical only resolves paths, without checking them on the filesystem (e.g. for the values written in tags)
+	Lexical bool
+
+	Layout string
+
+	Strict bool
+	// Target is the type to decode into when T itself doesn't say (e.g. T is any)
+	Target reflect.Type
+}
+
 func validateUrl(v string) (string, error) {
+	if _, err := parseUrl(v, typeOptions{}); err != nil {
+		return "", err
+	}
+
+	return v, nil
+}
+
+func parseUrl(v string, opts typeOptions) (*url.URL, error) {
 	if v == "" {
-		return "", fmt.Errorf("%w. empty string", ErrInvalidUrl)
+		return nil, fmt.Errorf("%w. empty string", ErrInvalidUrl)
 	}
 
-	_, err := url.ParseRequestURI(v)
+	u, err := url.ParseRequestURI(v)
 	if err != nil {
-		return "", fmt.Errorf("%w. unable to parse '%s' as URL: %v", ErrInvalidUrl, v, err)
+		return nil, fmt.Errorf("%w. unable to parse '%s' as URL: %v", ErrInvalidUrl, v, err)
 	}
 
-	return v, nil
+	if len(opts.Schemes) > 0 && !slices.ContainsFunc(opts.Schemes, func(s string) bool { return strings.EqualFold(s, u.Scheme) }) {
+		return nil, fmt.Errorf("%w. scheme '%s' is not allowed (allowed: %s)", ErrInvalidUrl, u.Scheme, strings.Join(opts.Schemes, "|"))
+	}
+
+	if opts.RequirePath && (u.Path == "" || u.Path == "/") {
+		return nil, fmt.Errorf("%w. '%s' has no path", ErrInvalidUrl, v)
+	}
+
+	if opts.NoUserinfo && u.User != nil {
+		return nil, fmt.Errorf("%w. credentials are not allowed in the URL", ErrInvalidUrl)
+	}
+
+	return u, nil
 }
 
 func validateEmail(v string) (string, error) {
@@ -75,7 +124,281 @@ func validateEmail(v string) (string, error) {
 	return v, nil
 }
 
+func validatePath(t VariableType, v string, opts typeOptions) (string, error) {
+	sentinel := ErrInvalidPath
+	switch t {
+	case TypeFile:
+		sentinel = ErrInvalidFile
+	case TypeDir:
+		sentinel = ErrInvalidDir
+	}
+
+	if v == "" {
+		return "", fmt.Errorf("%w. empty string", sentinel)
+	}
+
+	path, err := expandHome(v)
+	if err != nil {
+		return "", fmt.Errorf("%w. unable to expand '%s': %v", sentinel, v, err)
+	}
+
+	if opts.Abs {
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("%w. unable to resolve '%s': %v", sentinel, v, err)
+		}
+	}
+
+	if opts.Lexical {
+		return path, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil && (t != TypePath || opts.Exists || !os.IsNotExist(err)) {
+		return "", fmt.Errorf("%w. %v", sentinel, err)
+	}
+
+	switch {
+	case t == TypeFile && !info.Mode().IsRegular():
+		return "", fmt.Errorf("%w. '%s' is not a regular file", sentinel, path)
+	case t == TypeDir && !info.IsDir():
+		return "", fmt.Errorf("%w. '%s' is not a directory", sentinel, path)
+	}
+
+	if opts.Readable && info != nil {
+		if err := checkReadable(path); err != nil {
+			return "", fmt.Errorf("%w. '%s' is not readable: %v", sentinel, path, err)
+		}
+	}
+
+	if opts.Writable {
+		if err := checkWritable(path, info); err != nil {
+			return "", fmt.Errorf("%w. '%s' is not writable: %v", sentinel, path, err)
+		}
+	}
+
+	return path, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func checkWritable(path string, info os.FileInfo) error {
+	// for paths that don't exist yet, check that they could be created
+	if info == nil {
+		parent := filepath.Dir(path)
+		parentInfo, err := os.Stat(parent)
+		if err != nil {
+			return err
+		}
+
+		return checkWritable(parent, parentInfo)
+	}
+
+	return canWrite(path, info)
+}
+
+func validateTime(v string, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	tm, err := time.Parse(layout, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w. unable to parse '%s' with layout '%s': %v", ErrInvalidTime, v, layout, err)
+	}
+
+	return tm, nil
+}
+
+func validateDate(v string, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = time.DateOnly
+	}
+
+	tm, err := time.Parse(layout, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w. unable to parse '%s' with layout '%s': %v", ErrInvalidDate, v, layout, err)
+	}
+
+	return tm, nil
+}
+
+func validateDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w. unable to parse '%s' as duration: %v", ErrInvalidDuration, v, err)
+	}
+
+	return d, nil
+}
+
+func validateLocation(v string) (*time.Location, error) {
+	if v == "" {
+		return nil, fmt.Errorf("%w. empty string", ErrInvalidLocation)
+	}
+
+	loc, err := time.LoadLocation(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w. unable to load '%s': %v", ErrInvalidLocation, v, err)
+	}
+
+	return loc, nil
+}
+
+var (
+	uuidPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	ulidPattern   = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+)
+
+func validateUuid(v string) (string, error) {
+	if !uuidPattern.MatchString(v) {
+		return "", fmt.Errorf("%w. '%s' is not formatted as xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", ErrInvalidUuid, v)
+	}
+
+	return v, nil
+}
+
+func validateSemver(v string) (string, error) {
+	if !semverPattern.MatchString(v) {
+		return "", fmt.Errorf("%w. '%s' is not formatted as MAJOR.MINOR.PATCH", ErrInvalidSemver, v)
+	}
+
+	return v, nil
+}
+
+func validateHex(v string) (string, error) {
+	if v == "" {
+		return "", fmt.Errorf("%w. empty string", ErrInvalidHex)
+	}
+
+	if _, err := hex.DecodeString(v); err != nil {
+		return "", fmt.Errorf("%w. unable to decode '%s': %v", ErrInvalidHex, v, err)
+	}
+
+	return v, nil
+}
+
+func validateBase64(v string) ([]byte, error) {
+	if v == "" {
+		return nil, fmt.Errorf("%w. empty string", ErrInvalidBase64)
+	}
+
+	var err error
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		var decoded []byte
+		if decoded, err = encoding.DecodeString(v); err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w. unable to decode '%s': %v", ErrInvalidBase64, v, err)
+}
+
+func validateUlid(v string) (string, error) {
+	if !ulidPattern.MatchString(v) {
+		return "", fmt.Errorf("%w. '%s' is not a 26 characters Crockford base32 string", ErrInvalidUlid, v)
+	}
+
+	return v, nil
+}
+
+func validateEnum[T any](v string, opts typeOptions) (T, error) {
+	var zero T
+
+	target := targetType[T](opts)
+	e, ok := lookupEnum(target)
+	if !ok {
+		return zero, fmt.Errorf("%w. %s is not a registered enum", ErrInvalidEnum, target)
+	}
+
+	value, err := e.Parse(v)
+	if err != nil {
+		return zero, err
+	}
+
+	// pointer targets (e.g. Enum[*Env]) get a pointer to the value
+	if typ := reflect.TypeFor[T](); typ.Kind() == reflect.Pointer {
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(reflect.ValueOf(value))
+		return ptr.Interface().(T), nil
+	}
+
+	return value.(T), nil
+}
+
+// targetType returns the type a value should be loaded as
+func targetType[T any](opts typeOptions) reflect.Type {
+	target := reflect.TypeFor[T]()
+	if target.Kind() == reflect.Interface && opts.Target != nil {
+		return opts.Target
+	}
+
+	return target
+}
+
+func validateJson[T any](v string, opts typeOptions) (T, error) {
+	var zero T
+
+	target := targetType[T](opts)
+
+	decoder := json.NewDecoder(strings.NewReader(v))
+	if opts.Strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	decoded := reflect.New(target)
+	if err := decoder.Decode(decoded.Interface()); err != nil {
+		return zero, fmt.Errorf("%w. unable to decode into %s: %v", ErrInvalidJson, target, err)
+	}
+
+	if decoder.More() {
+		return zero, fmt.Errorf("%w. unexpected data after the JSON value", ErrInvalidJson)
+	}
+
+	return decoded.Elem().Interface().(T), nil
+}
+
+// typed returns the parsed value, or the raw string if the target is a string
+// (e.g. T is string, or T is any and the field is a string)
+func typed[T any](parsed any, raw string, opts typeOptions) T {
+	target := targetType[T](opts)
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	if target.Kind() == reflect.String {
+		return reflect.ValueOf(raw).Convert(target).Interface().(T)
+	}
+
+	return parsed.(T)
+}
+
 func validateType[T any](t VariableType, v string) (T, error) {
+	return validateTypeWith[T](t, v, typeOptions{})
+}
+
+func validateTypeWith[T any](t VariableType, v string, opts typeOptions) (T, error) {
 	var zero T
 
 	switch t {
@@ -94,25 +417,125 @@ func validateType[T any](t VariableType, v string) (T, error) {
 		p, err := validatePort(v)
 		return any(p).(T), err
 	case TypeUrl:
-		u, err := validateUrl(v)
-		return any(u).(T), err
+		u, err := parseUrl(v, opts)
+		if err != nil {
+			return zero, err
+		}
+
+		switch any(zero).(type) {
+		case *url.URL:
+			return any(u).(T), nil
+		case url.URL:
+			return any(*u).(T), nil
+		}
+
+		return any(v).(T), nil
 	case TypeEmail:
 		em, err := validateEmail(v)
 		return any(em).(T), err
+	case TypeFile, TypeDir, TypePath:
+		p, err := validatePath(t, v, opts)
+		return any(p).(T), err
+	case TypeTime:
+		tm, err := validateTime(v, opts.Layout)
+		if err != nil {
+			return zero, err
+		}
+
+		return typed[T](tm, v, opts), nil
+	case TypeDate:
+		d, err := validateDate(v, opts.Layout)
+		if err != nil {
+			return zero, err
+		}
+
+		return typed[T](d, v, opts), nil
+	case TypeDuration:
+		d, err := validateDuration(v)
+		if err != nil {
+			return zero, err
+		}
+
+		return typed[T](d, v, opts), nil
+	case TypeLocation:
+		loc, err := validateLocation(v)
+		if err != nil {
+			return zero, err
+		}
+
+		return typed[T](loc, v, opts), nil
+	case TypeUuid:
+		id, err := validateUuid(v)
+		return any(id).(T), err
+	case TypeSemver:
+		ver, err := validateSemver(v)
+		return any(ver).(T), err
+	case TypeHex:
+		h, err := validateHex(v)
+		return any(h).(T), err
+	case TypeBase64:
+		b, err := validateBase64(v)
+		if err != nil {
+			return zero, err
+		}
+
+		// strings can hold arbitrary bytes, so string targets get the decoded data too
+		return typed[T](b, string(b), opts), nil
+	case TypeUlid:
+		id, err := validateUlid(v)
+		return any(id).(T), err
+	case TypeJson:
+		return validateJson[T](v, opts)
+	case TypeEnum:
+		return validateEnum[T](v, opts)
 	}
 
 	return zero, fmt.Errorf("Err: %w. Reason: unknown type '%s'", ErrUnknownType, t)
 }
 
-func validate[T comparable](variable Variable[T], value string) (T, error) {
-	validated, err := validateType[T](variable.Type, value)
+// pathTypes are the types checked on the filesystem
+var pathTypes = []VariableType{TypeFile, TypeDir, TypePath}
+
+// isPathType reports whether the type is checked on the filesystem
+func isPathType(t VariableType) bool {
+	return slices.Contains(pathTypes, t)
+}
+
+func validate[T any](variable Variable[T], value string) (T, error) {
+	return validateWith(variable, value, variable.typeOptions())
+}
+
+func validateWith[T any](variable Variable[T], value string, opts typeOptions) (T, error) {
+	validated, err := validateTypeWith[T](variable.Type, value, opts)
 	if err != nil {
 		return *new(T), err
 	}
 
-	if len(variable.Oneof) > 0 && !slices.Contains(variable.Oneof, validated) {
-		return *new(T), fmt.Errorf("%w. Available choices: %v", ErrNotInOneof, variable.Oneof)
+	if len(variable.Oneof) > 0 {
+		choice, ok := matchOneof(variable, validated)
+		if !ok {
+			return *new(T), fmt.Errorf("%w. Available choices: %v", ErrNotInOneof, variable.Oneof)
+		}
+
+		if variable.Normalize {
+			validated = choice
+		}
 	}
 
 	return validated, nil
 }
+
+// matchOneof returns the choice matching the value
+func matchOneof[T any](variable Variable[T], value T) (T, bool) {
+	for _, choice := range variable.Oneof {
+		if reflect.DeepEqual(choice, value) {
+			return choice, true
+		}
+
+		if variable.OneofFold && strings.EqualFold(strings.TrimSpace(fmt.Sprint(choice)), strings.TrimSpace(fmt.Sprint(value))) {
+			return choice, true
+		}
+	}
+
+	return *new(T), false
+}
@@ -0,0 +1,128 @@
+package environ
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Value holds a configuration that can be read by many goroutines while it's reloaded
+type Value[T any] struct {
+	config   atomic.Pointer[T]
+	reloader *reloader[T]
+
+	// mu serializes the reloads and guards the subscribers and the pending changes.
+	// It's not held while the subscribers are called.
+	mu          sync.Mutex
+	subscribers []*func(Change[T])
+	// pending are the changes left to notify, in the order of the reloads.
+	// delivering is set while a goroutine notifies them, the other reloads only queue theirs.
+	pending    []Change[T]
+	delivering bool
+}
+
+// NewValue loads T into a new Value
+func NewValue[T any](opts ...Option) (*Value[T], error) {
+	r, err := newReloader[T](newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	// the reloader replaces its configuration while it's read, so the value holds a copy
+	config := r.config
+	v := &Value[T]{reloader: r}
+	v.config.Store(&config)
+	return v, nil
+}
+
+// Load returns the current configuration
+func (v *Value[T]) Load() T {
+	return *v.config.Load()
+}
+
+// Reload loads T again and replaces the current configuration if it's valid.
+// Subscribers are notified when a field changed, when the reload failed,
+// or when the changed static fields differ from the last notified ones.
+// Changes are notified in the order of the reloads: when another goroutine is notifying
+// the subscribers, Reload leaves its change to it and returns without waiting.
+// Static fields are never reloaded, changing them returns ErrRestartRequired.
+func (v *Value[T]) Reload() error {
+	v.mu.Lock()
+
+	change, publish := v.reloader.reload()
+	if !publish {
+		v.mu.Unlock()
+		return change.Err
+	}
+
+	if len(change.Fields) > 0 {
+		v.config.Store(&change.Config)
+	}
+
+	v.pending = append(v.pending, change)
+	v.deliver()
+
+	return change.Err
+}
+
+// deliver notifies the subscribers of the pending changes unless another goroutine is already doing it.
+// It's called with mu held and releases it.
+func (v *Value[T]) deliver() {
+	if v.delivering {
+		v.mu.Unlock()
+		return
+	}
+
+	v.delivering = true
+	for len(v.pending) > 0 {
+		change := v.pending[0]
+		v.pending = v.pending[1:]
+
+		// subscribers are notified without the lock, so they can unsubscribe or reload
+		subscribers := slices.Clone(v.subscribers)
+		v.mu.Unlock()
+
+		for _, subscriber := range subscribers {
+			(*subscriber)(change)
+		}
+
+		v.mu.Lock()
+	}
+
+	v.delivering = false
+	v.mu.Unlock()
+}
+
+// Subscribe calls fn with every change made by Reload, until unsubscribe is called
+func (v *Value[T]) Subscribe(fn func(Change[T])) (unsubscribe func()) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	subscriber := &fn
+	v.subscribers = append(v.subscribers, subscriber)
+
+	return func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		v.subscribers = slices.DeleteFunc(v.subscribers, func(s *func(Change[T])) bool {
+			return s == subscriber
+		})
+	}
+}
+
+// Watch reloads the configuration on the triggers configured for Watch (SIGHUP by default), until ctx is done
+func (v *Value[T]) Watch(ctx context.Context) {
+	reloads := v.reloader.opts.reloads(ctx)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloads:
+				v.Reload()
+			}
+		}
+	}()
+}
@@ -0,0 +1,220 @@
+package environ
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	t.Run("loads the configuration", func(t *testing.T) {
+		value, err := NewValue[testWatchConfig]()
+		assert.NoError(t, err)
+		assert.Equal(t, testWatchConfig{Level: "info", Limit: 10}, value.Load())
+	})
+
+	t.Run("returns error when the first load fails", func(t *testing.T) {
+		os.Setenv("TEST_RATE_LIMIT", "abc")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		_, err := NewValue[testWatchConfig]()
+		assert.ErrorIs(t, err, ErrInvalidInt)
+	})
+
+	t.Run("reloads and notifies subscribers", func(t *testing.T) {
+		value, err := NewValue[testWatchConfig]()
+		assert.NoError(t, err)
+
+		var changes []Change[testWatchConfig]
+		unsubscribe := value.Subscribe(func(change Change[testWatchConfig]) {
+			changes = append(changes, change)
+		})
+
+		assert.NoError(t, value.Reload())
+		assert.Empty(t, changes)
+
+		os.Setenv("TEST_RATE_LIMIT", "20")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		assert.NoError(t, value.Reload())
+		assert.Equal(t, 20, value.Load().Limit)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, []string{"Limit"}, changes[0].Fields)
+
+		unsubscribe()
+		os.Setenv("TEST_RATE_LIMIT", "30")
+
+		assert.NoError(t, value.Reload())
+		assert.Equal(t, 30, value.Load().Limit)
+		assert.Len(t, changes, 1)
+	})
+
+	t.Run("can unsubscribe and reload from a subscriber", func(t *testing.T) {
+		value, err := NewValue[testWatchConfig]()
+		assert.NoError(t, err)
+
+		calls := 0
+		var unsubscribe func()
+		unsubscribe = value.Subscribe(func(change Change[testWatchConfig]) {
+			calls++
+			unsubscribe()
+			value.Reload()
+		})
+
+		os.Setenv("TEST_RATE_LIMIT", "20")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		done := make(chan error, 1)
+		go func() { done <- value.Reload() }()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("reload deadlocked")
+		}
+
+		os.Setenv("TEST_RATE_LIMIT", "30")
+		assert.NoError(t, value.Reload())
+		assert.Equal(t, 30, value.Load().Limit)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("keeps the configuration when the reload fails", func(t *testing.T) {
+		value, err := NewValue[testWatchConfig]()
+		assert.NoError(t, err)
+
+		var change Change[testWatchConfig]
+		value.Subscribe(func(c Change[testWatchConfig]) { change = c })
+
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+		os.Setenv("TEST_RATE_LIMIT", "abc")
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		err = value.Reload()
+		assert.ErrorIs(t, err, ErrInvalidInt)
+		assert.ErrorIs(t, change.Err, ErrInvalidInt)
+		assert.Equal(t, testWatchConfig{Level: "info", Limit: 10}, value.Load())
+
+		// the same error is returned again but notified once
+		change = Change[testWatchConfig]{}
+		assert.ErrorIs(t, value.Reload(), ErrInvalidInt)
+		assert.NoError(t, change.Err)
+	})
+
+	t.Run("keeps static fields and reports restart required", func(t *testing.T) {
+		type Config struct {
+			Port  int    `env:"name=TEST_PORT, type=port, default=8080, static"`
+			Level string `env:"name=TEST_LOG_LEVEL, default=info"`
+		}
+
+		value, err := NewValue[Config]()
+		assert.NoError(t, err)
+
+		os.Setenv("TEST_PORT", "9090")
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+		defer os.Unsetenv("TEST_PORT")
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+
+		calls := 0
+		value.Subscribe(func(Change[Config]) { calls++ })
+
+		err = value.Reload()
+		assert.ErrorIs(t, err, ErrRestartRequired)
+		assert.Contains(t, err.Error(), "Port")
+		assert.Equal(t, Config{Port: 8080, Level: "debug"}, value.Load())
+
+		// the same static change is returned again but notified once
+		assert.ErrorIs(t, value.Reload(), ErrRestartRequired)
+		assert.ErrorIs(t, value.Reload(), ErrRestartRequired)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("can be read while reloading", func(t *testing.T) {
+		value, err := NewValue[testWatchConfig]()
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				for range 100 {
+					config := value.Load()
+					assert.Contains(t, []int{10, 20}, config.Limit)
+				}
+			})
+		}
+
+		os.Setenv("TEST_RATE_LIMIT", "20")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+		for range 10 {
+			wg.Go(func() { value.Reload() })
+		}
+
+		wg.Wait()
+		assert.Equal(t, 20, value.Load().Limit)
+	})
+
+	t.Run("notifies concurrent reloads in order", func(t *testing.T) {
+		// every lookup returns a greater limit, so every reload changes it
+		var limit atomic.Int64
+		source := SourceFunc(func(name string) (string, bool) {
+			if name != "TEST_RATE_LIMIT" {
+				return "", false
+			}
+
+			return strconv.FormatInt(limit.Add(1), 10), true
+		})
+
+		value, err := NewValue[testWatchConfig](WithSource(source))
+		assert.NoError(t, err)
+
+		var limits []int
+		value.Subscribe(func(change Change[testWatchConfig]) {
+			limits = append(limits, change.Config.Limit)
+		})
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Go(func() { value.Reload() })
+		}
+		wg.Wait()
+
+		assert.Len(t, limits, 50)
+		assert.IsIncreasing(t, limits)
+		assert.Equal(t, value.Load().Limit, limits[len(limits)-1])
+	})
+
+	t.Run("reloads on watch triggers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		value, err := NewValue[testWatchConfig](WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+
+		changed := make(chan struct{}, 1)
+		value.Subscribe(func(Change[testWatchConfig]) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		value.Watch(ctx)
+
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+
+		select {
+		case <-changed:
+			assert.Equal(t, "debug", value.Load().Level)
+		case <-time.After(time.Second):
+			t.Fatal("not reloaded")
+		}
+	})
+}
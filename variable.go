@@ -1,45 +1,116 @@
 package environ
 
 import (
+	"cmp"
 	"fmt"
-	"os"
+	"reflect"
+	"slices"
+	"strings"
 )
 
 type VariableType string
 
 var (
-	TypeString  VariableType = "string"
-	TypeInt     VariableType = "int"
-	TypeFloat   VariableType = "float"
-	TypeBoolean VariableType = "boolean"
-	TypePort    VariableType = "port"
-	TypeUrl     VariableType = "url"
-	TypeEmail   VariableType = "email"
+	TypeString   VariableType = "string"
+	TypeInt      VariableType = "int"
+	TypeFloat    VariableType = "float"
+	TypeBoolean  VariableType = "boolean"
+	TypePort     VariableType = "port"
+	TypeUrl      VariableType = "url"
+	TypeEmail    VariableType = "email"
+	TypeFile     VariableType = "file"
+	TypeDir      VariableType = "dir"
+	TypePath     VariableType = "path"
+	TypeTime     VariableType = "time"
+	TypeDate     VariableType = "date"
+	TypeLocation VariableType = "location"
+	TypeDuration VariableType = "duration"
+	TypeUuid     VariableType = "uuid"
+	TypeSemver   VariableType = "semver"
+	TypeHex      VariableType = "hex"
+	TypeBase64   VariableType = "base64"
+	TypeUlid     VariableType = "ulid"
+	TypeJson     VariableType = "json"
+	TypeEnum     VariableType = "enum"
 )
 
-type VariableValidator[T comparable] func(T) (T, error)
+type VariableValidator[T any] func(T) (T, error)
 
-type Variable[T comparable] struct {
+type Variable[T any] struct {
 	Name        string       `tag:"env | get('name')"`
+	Aliases     []string     `env:"-"`
+	Deprecated  []string     `tag:"env | get('deprecated') | split('|')"`
 	Type        VariableType `tag:"env | get('type')"`
 	Default     *T           `tag:"env | get('default')"`
 	Optional    bool         `tag:"env | has('optional')"`
 	Description string       `tag:"env | get('desc')"`
-	Oneof       []T          `tag:"env | get('oneof') | split('|')"`
+	Oneof       []T          `tag:"env | get('oneof') | default(get(env, 'oneof_ci')) | split('|')"`
+	OneofFold   bool         `tag:"env | has('oneof_ci')"`
+	Normalize   bool         `tag:"env | has('normalize')"`
+	AllowEmpty  bool         `tag:"env | has('allow_empty')"`
+	Expand      bool         `tag:"env | has('expand')"`
+	Static      bool         `tag:"env | has('static')"`
+
+	RequiredIf     string   `tag:"env | get('required_if')"`
+	RequiredUnless string   `tag:"env | get('required_unless')"`
+	RequiredWith   []string `tag:"env | get('required_with') | split('|')"`
+	ExcludedWith   []string `tag:"env | get('excluded_with') | split('|')"`
+
+	Check string `tag:"env | get('check')"`
+
+	Schemes     []string `tag:"env | get('schemes') | split('|')"`
+	RequirePath bool     `tag:"env | has('require_path')"`
+	NoUserinfo  bool     `tag:"env | has('no_userinfo')"`
+
+	Exists   bool `tag:"env | has('exists')"`
+	Readable bool `tag:"env | has('readable')"`
+	Writable bool `tag:"env | has('writable')"`
+	Abs      bool `tag:"env | has('abs')"`
+
+	Layout string `tag:"env | get('layout')"`
+
+	Strict bool `tag:"env | has('strict')"`
 
 	Validator VariableValidator[T] `env:"-"`
+
+	// target is the type of the struct field the variable is loaded into, if any
+	target reflect.Type
+	// rawDefault is the default value as written in the tag, before it's converted to the variable's type
+	rawDefault string
 }
 
 // Load will fetch the environment variable, validate it, and return the value or an error
 func (v Variable[T]) Load() (T, error) {
-	validated, err := loadVariable(v)
+	return v.LoadFrom(defaultLoader)
+}
+
+// LoadFrom is like Load but uses the loader's options (e.g. its source)
+func (v Variable[T]) LoadFrom(loader *Loader) (T, error) {
+	v = v.prefixed(loader.opts.prefix)
+
+	validated, err := loadVariable(v, loader.opts)
 	if err != nil {
-		return *new(T), fmt.Errorf("Err: variable %q. Reason: %w", v.Name, err)
+		return *new(T), &VariableError{v.Name, err}
 	}
 
 	return validated, nil
 }
 
+// LoadMaybe is like Load but also reports whether the variable was set, empty, or absent,
+// and which of its names was used
+func (v Variable[T]) LoadMaybe() (Maybe[T], error) {
+	return v.prefixed(defaultLoader.opts.prefix).loadMaybe(defaultLoader.opts)
+}
+
+func (v Variable[T]) loadMaybe(opts options) (Maybe[T], error) {
+	validated, name, presence, err := lookupVariable(v, opts)
+	if err != nil {
+		return Maybe[T]{}, &VariableError{v.Name, err}
+	}
+
+	return Maybe[T]{Value: validated, Presence: presence, Name: name}, nil
+}
+
 // MustLoad is like Load but will panic if there is an error
 func (v Variable[T]) MustLoad() T {
 	validated, err := v.Load()
@@ -50,7 +121,7 @@ func (v Variable[T]) MustLoad() T {
 	return validated
 }
 
-type VariableBuilder[T comparable] struct {
+type VariableBuilder[T any] struct {
 	Variable[T]
 }
 
@@ -64,6 +135,45 @@ func (vb VariableBuilder[T]) Oneof(choices ...T) VariableBuilder[T] {
 	return vb
 }
 
+// OneofFold is like Oneof but ignores case and surrounding whitespace
+func (vb VariableBuilder[T]) OneofFold(choices ...T) VariableBuilder[T] {
+	vb.Variable.Oneof = choices
+	vb.Variable.OneofFold = true
+	return vb
+}
+
+// Normalize will replace the value by the oneof choice it matched
+func (vb VariableBuilder[T]) Normalize() VariableBuilder[T] {
+	vb.Variable.Normalize = true
+	return vb
+}
+
+// Alias adds other names to look the variable up with, tried in order after the main name
+func (vb VariableBuilder[T]) Alias(names ...string) VariableBuilder[T] {
+	vb.Variable.Aliases = append(vb.Variable.Aliases, names...)
+	return vb
+}
+
+// Deprecated adds old names to look the variable up with.
+// The deprecation handler is called when the variable is found under one of them (see WithDeprecationHandler).
+func (vb VariableBuilder[T]) Deprecated(names ...string) VariableBuilder[T] {
+	vb.Variable.Deprecated = append(vb.Variable.Deprecated, names...)
+	return vb
+}
+
+// AllowEmpty will keep a variable set to an empty string instead of treating it as missing
+func (vb VariableBuilder[T]) AllowEmpty() VariableBuilder[T] {
+	vb.Variable.AllowEmpty = true
+	return vb
+}
+
+// Expand will replace ${VAR} and ${VAR:-fallback} references in the value and default
+// by the value of the referenced variables ("$${" is kept as a literal "${")
+func (vb VariableBuilder[T]) Expand() VariableBuilder[T] {
+	vb.Variable.Expand = true
+	return vb
+}
+
 func (vb VariableBuilder[T]) Default(value T) VariableBuilder[T] {
 	vb.Variable.Default = &value
 	return vb
@@ -74,38 +184,295 @@ func (vb VariableBuilder[T]) Desc(desc string) VariableBuilder[T] {
 	return vb
 }
 
+// Schemes restricts the accepted URL schemes (e.g. "https", "postgres")
+func (vb VariableBuilder[T]) Schemes(schemes ...string) VariableBuilder[T] {
+	vb.Variable.Schemes = schemes
+	return vb
+}
+
+// RequirePath will reject URLs without a path
+func (vb VariableBuilder[T]) RequirePath() VariableBuilder[T] {
+	vb.Variable.RequirePath = true
+	return vb
+}
+
+// NoUserinfo will reject URLs containing credentials (user:password@)
+func (vb VariableBuilder[T]) NoUserinfo() VariableBuilder[T] {
+	vb.Variable.NoUserinfo = true
+	return vb
+}
+
+// Exists will ensure the path exists (always the case for File and Dir)
+func (vb VariableBuilder[T]) Exists() VariableBuilder[T] {
+	vb.Variable.Exists = true
+	return vb
+}
+
+// Readable will ensure the path can be opened for reading
+func (vb VariableBuilder[T]) Readable() VariableBuilder[T] {
+	vb.Variable.Readable = true
+	return vb
+}
+
+// Writable will ensure the path can be written to
+func (vb VariableBuilder[T]) Writable() VariableBuilder[T] {
+	vb.Variable.Writable = true
+	return vb
+}
+
+// Abs will resolve the path to an absolute, cleaned path
+func (vb VariableBuilder[T]) Abs() VariableBuilder[T] {
+	vb.Variable.Abs = true
+	return vb
+}
+
+// Layout sets the time.Parse layout used by Time and Date
+func (vb VariableBuilder[T]) Layout(layout string) VariableBuilder[T] {
+	vb.Variable.Layout = layout
+	return vb
+}
+
+// Strict will reject JSON objects with fields unknown to the target type
+func (vb VariableBuilder[T]) Strict() VariableBuilder[T] {
+	vb.Variable.Strict = true
+	return vb
+}
+
+// Check will ensure the expression holds for the loaded value (e.g. "value % 2 == 0").
+// See https://expr-lang.org for the syntax.
+func (vb VariableBuilder[T]) Check(expression string) VariableBuilder[T] {
+	vb.Variable.Check = expression
+	return vb
+}
+
 func (vb VariableBuilder[T]) Validate(validator VariableValidator[T]) VariableBuilder[T] {
 	vb.Variable.Validator = validator
 	return vb
 }
 
-func loadVariable[T comparable](variable Variable[T]) (T, error) {
+func (v Variable[T]) typeOptions() typeOptions {
+	return typeOptions{
+		Schemes:     v.Schemes,
+		RequirePath: v.RequirePath,
+		NoUserinfo:  v.NoUserinfo,
+		Exists:      v.Exists,
+		Readable:    v.Readable,
+		Writable:    v.Writable,
+		Abs:         v.Abs,
+		Layout:      v.Layout,
+		Strict:      v.Strict,
+		Target:      v.target,
+	}
+}
+
+// typeSpecificOption is an option that only applies to some variable types
+type typeSpecificOption struct {
+	// key is the option's tag key
+	key   string
+	set   bool
+	types []VariableType
+}
+
+// checkOptions returns an error when an option set on the variable doesn't apply to its type (e.g. schemes on an int)
+func (v Variable[T]) checkOptions() error {
+	options := []typeSpecificOption{
+		{"schemes", len(v.Schemes) > 0, []VariableType{TypeUrl}},
+		{"require_path", v.RequirePath, []VariableType{TypeUrl}},
+		{"no_userinfo", v.NoUserinfo, []VariableType{TypeUrl}},
+		{"exists", v.Exists, pathTypes},
+		{"readable", v.Readable, pathTypes},
+		{"writable", v.Writable, pathTypes},
+		{"abs", v.Abs, pathTypes},
+		{"layout", v.Layout != "", []VariableType{TypeTime, TypeDate}},
+		{"strict", v.Strict, []VariableType{TypeJson}},
+	}
+
+	for _, option := range options {
+		if option.set && !slices.Contains(option.types, v.Type) {
+			return fmt.Errorf("%w. '%s' can't be used with type '%s'", ErrInvalidOption, option.key, cmp.Or(v.Type, TypeString))
+		}
+	}
+
+	return nil
+}
+
+// zero returns the zero value of the variable's type,
+// using the target type when T doesn't say (e.g. T is any).
+func (v Variable[T]) zero() T {
+	if v.target == nil || reflect.TypeFor[T]().Kind() != reflect.Interface {
+		return *new(T)
+	}
+
+	target := v.target
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	return reflect.Zero(target).Interface().(T)
+}
+
+// defaultValue returns the default value, expanding and validating it when it references other variables,
+// and checking it on the filesystem and against the choices for path types
+func (v Variable[T]) defaultValue(source Source) (T, error) {
+	raw, ok := any(*v.Default).(string)
+	if !ok {
+		return *v.Default, nil
+	}
+
+	if v.Expand && strings.Contains(raw, "${") {
+		expanded, err := expand(source, v.Name, raw)
+		if err != nil {
+			return *new(T), err
+		}
+
+		return validate(v, expanded)
+	}
+
+	// default paths are only checked on the filesystem when they're used, and still have to be a choice
+	if isPathType(v.Type) {
+		return validate(v, raw)
+	}
+
+	return *v.Default, nil
+}
+
+func loadVariable[T any](variable Variable[T], opts options) (T, error) {
+	validated, _, _, err := lookupVariable(variable, opts)
+	return validated, err
+}
+
+// names returns every name of the variable, in lookup order
+func (v Variable[T]) names() []string {
+	names := append([]string{v.Name}, v.Aliases...)
+	return append(names, v.Deprecated...)
+}
+
+// prefixed returns the variable with the prefix prepended to its names
+// and to the names of the variables its rules refer to
+func (v Variable[T]) prefixed(prefix string) Variable[T] {
+	if prefix == "" {
+		return v
+	}
+
+	addPrefix := func(names []string) []string {
+		if len(names) == 0 {
+			return names
+		}
+
+		prefixed := make([]string, len(names))
+		for i, name := range names {
+			prefixed[i] = prefix + name
+		}
+
+		return prefixed
+	}
+
+	v.Name = prefix + v.Name
+	v.Aliases = addPrefix(v.Aliases)
+	v.Deprecated = addPrefix(v.Deprecated)
+
+	if v.RequiredIf != "" {
+		v.RequiredIf = prefix + v.RequiredIf
+	}
+	if v.RequiredUnless != "" {
+		v.RequiredUnless = prefix + v.RequiredUnless
+	}
+	v.RequiredWith = addPrefix(v.RequiredWith)
+	v.ExcludedWith = addPrefix(v.ExcludedWith)
+
+	return v
+}
+
+// lookup returns the first non-empty value found under one of the variable's names,
+// or the first empty one if there's none.
+func (v Variable[T]) lookup(source Source) (string, string, Presence) {
+	found := ""
+	presence := Absent
+
+	for _, name := range v.names() {
+		value, exists := source.Lookup(name)
+		switch {
+		case !exists:
+			continue
+		case value != "":
+			return name, value, Present
+		case presence == Absent:
+			found, presence = name, Empty
+		}
+	}
+
+	return found, "", presence
+}
+
+func lookupVariable[T any](variable Variable[T], opts options) (T, string, Presence, error) {
 	if variable.Name == "" {
-		return *new(T), ErrMissingName
+		return *new(T), "", Absent, ErrMissingName
+	}
+
+	if err := variable.checkOptions(); err != nil {
+		return *new(T), "", Absent, err
+	}
+
+	variable.Expand = variable.Expand || opts.expand
+
+	name, value, presence := variable.lookup(opts.source)
+	if presence != Absent && slices.Contains(variable.Deprecated, name) {
+		opts.onDeprecated(name, variable.Name)
 	}
 
-	value, exists := os.LookupEnv(variable.Name)
-	if !exists || value == "" {
+	if variable.Expand && presence == Present {
+		expanded, err := expand(opts.source, name, value)
+		if err != nil {
+			return *new(T), name, presence, err
+		}
+
+		value = expanded
+		if value == "" {
+			presence = Empty
+		}
+	}
+
+	if presence == Absent || (presence == Empty && !variable.AllowEmpty) {
 		if variable.Default != nil {
-			return *variable.Default, nil
+			validated, err := variable.defaultValue(opts.source)
+			return validated, name, presence, err
 		} else if variable.Optional {
-			return *new(T), nil
+			return *new(T), name, presence, nil
 		} else {
-			return *new(T), ErrMissingValue
+			return *new(T), name, presence, ErrMissingValue
 		}
 	}
 
+	// an allowed empty value is kept as the zero value, without validation
+	if presence == Empty {
+		return variable.zero(), name, presence, nil
+	}
+
 	validated, err := validate(variable, value)
 	if err != nil {
-		return *new(T), err
+		return *new(T), name, presence, err
 	}
 
 	if variable.Validator != nil {
 		validated, err = variable.Validator(validated)
 		if err != nil {
-			return *new(T), err
+			return *new(T), name, presence, err
 		}
 	}
 
-	return validated, nil
+	// struct fields are checked once their sibling fields are loaded
+	if variable.Check != "" && variable.target == nil {
+		value := reflect.ValueOf(validated)
+
+		program, err := compileCheck(variable.Check, value.Type(), nil)
+		if err != nil {
+			return *new(T), name, presence, err
+		}
+
+		if err := runCheck(program, variable.Check, value, reflect.Value{}); err != nil {
+			return *new(T), name, presence, err
+		}
+	}
+
+	return validated, name, presence, nil
 }
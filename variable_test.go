@@ -1,7 +1,9 @@
 package environ
 
 import (
+	"bytes"
 	"errors"
+	"log/slog"
 	"os"
 	"testing"
 
@@ -14,7 +16,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "",
 			Type: TypeString,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrMissingName)
 		assert.Equal(t, "", result)
@@ -28,7 +30,7 @@ func TestLoadVariable(t *testing.T) {
 			Type:    TypeString,
 			Default: &defaultValue,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "default", result)
 	})
@@ -42,7 +44,7 @@ func TestLoadVariable(t *testing.T) {
 			Type:    TypeString,
 			Default: &defaultValue,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "default", result)
 	})
@@ -54,7 +56,7 @@ func TestLoadVariable(t *testing.T) {
 			Type:     TypeString,
 			Optional: true,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "", result)
 	})
@@ -67,18 +69,47 @@ func TestLoadVariable(t *testing.T) {
 			Type:     TypeInt,
 			Optional: true,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 0, result)
 	})
 
+	t.Run("keeps empty value when allowed", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "")
+		defer os.Unsetenv("TEST_VAR")
+		defaultValue := "default"
+		variable := Variable[string]{
+			Name:       "TEST_VAR",
+			Type:       TypeString,
+			Default:    &defaultValue,
+			AllowEmpty: true,
+		}
+		result, err := loadVariable(variable, newOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "", result)
+	})
+
+	t.Run("uses default when absent even if empty is allowed", func(t *testing.T) {
+		os.Unsetenv("TEST_VAR")
+		defaultValue := "default"
+		variable := Variable[string]{
+			Name:       "TEST_VAR",
+			Type:       TypeString,
+			Default:    &defaultValue,
+			AllowEmpty: true,
+		}
+		result, err := loadVariable(variable, newOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "default", result)
+	})
+
 	t.Run("returns error when env var does not exist and not optional", func(t *testing.T) {
 		os.Unsetenv("TEST_VAR")
 		variable := Variable[string]{
 			Name: "TEST_VAR",
 			Type: TypeString,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrMissingValue)
 		assert.Equal(t, "", result)
@@ -91,7 +122,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeInt,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 42, result)
 	})
@@ -103,7 +134,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeInt,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrInvalidInt)
 		assert.Equal(t, 0, result)
@@ -119,7 +150,7 @@ func TestLoadVariable(t *testing.T) {
 				return v * 2, nil
 			},
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 20, result)
 	})
@@ -135,7 +166,7 @@ func TestLoadVariable(t *testing.T) {
 				return 0, customErr
 			},
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, customErr)
 		assert.Equal(t, 0, result)
@@ -149,7 +180,7 @@ func TestLoadVariable(t *testing.T) {
 			Type:  TypeInt,
 			Oneof: []int{80, 443, 8080},
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 8080, result)
 	})
@@ -163,7 +194,7 @@ func TestLoadVariable(t *testing.T) {
 			Default:  &defaultValue,
 			Optional: true,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "from_default", result)
 	})
@@ -175,7 +206,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeString,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "hello world", result)
 	})
@@ -187,7 +218,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeBoolean,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.True(t, result)
 	})
@@ -199,7 +230,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeFloat,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 3.14, result)
 	})
@@ -211,7 +242,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypePort,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, 8080, result)
 	})
@@ -223,7 +254,7 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeUrl,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "https://example.com", result)
 	})
@@ -235,12 +266,88 @@ func TestLoadVariable(t *testing.T) {
 			Name: "TEST_VAR",
 			Type: TypeEmail,
 		}
-		result, err := loadVariable(variable)
+		result, err := loadVariable(variable, newOptions(nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "user@example.com", result)
 	})
 }
 
+func TestLookupVariableNames(t *testing.T) {
+	t.Run("tries names in order", func(t *testing.T) {
+		os.Unsetenv("NEW_VAR")
+		os.Setenv("OLD_VAR", "old")
+		os.Setenv("OLDER_VAR", "older")
+		defer os.Unsetenv("OLD_VAR")
+		defer os.Unsetenv("OLDER_VAR")
+
+		variable := Variable[string]{Name: "NEW_VAR", Aliases: []string{"OLD_VAR", "OLDER_VAR"}}
+		result, name, presence, err := lookupVariable(variable, newOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", result)
+		assert.Equal(t, "OLD_VAR", name)
+		assert.Equal(t, Present, presence)
+	})
+
+	t.Run("prefers the main name", func(t *testing.T) {
+		os.Setenv("NEW_VAR", "new")
+		os.Setenv("OLD_VAR", "old")
+		defer os.Unsetenv("NEW_VAR")
+		defer os.Unsetenv("OLD_VAR")
+
+		variable := Variable[string]{Name: "NEW_VAR", Deprecated: []string{"OLD_VAR"}}
+		result, name, _, err := lookupVariable(variable, newOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", result)
+		assert.Equal(t, "NEW_VAR", name)
+	})
+
+	t.Run("skips empty values", func(t *testing.T) {
+		os.Setenv("NEW_VAR", "")
+		os.Setenv("OLD_VAR", "old")
+		defer os.Unsetenv("NEW_VAR")
+		defer os.Unsetenv("OLD_VAR")
+
+		variable := Variable[string]{Name: "NEW_VAR", Aliases: []string{"OLD_VAR"}}
+		result, _, _, err := lookupVariable(variable, newOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", result)
+	})
+
+	t.Run("calls the deprecation handler for deprecated names", func(t *testing.T) {
+		os.Unsetenv("NEW_VAR")
+		os.Setenv("OLD_VAR", "old")
+		defer os.Unsetenv("OLD_VAR")
+
+		var calls [][2]string
+		loader := New(WithDeprecationHandler(func(deprecated, name string) {
+			calls = append(calls, [2]string{deprecated, name})
+		}))
+
+		_, err := Url("NEW_VAR").Deprecated("OLD_VAR").LoadFrom(loader)
+		assert.ErrorIs(t, err, ErrInvalidUrl)
+		assert.Equal(t, [][2]string{{"OLD_VAR", "NEW_VAR"}}, calls)
+
+		_, err = String("NEW_VAR").Alias("OLD_VAR").LoadFrom(loader)
+		assert.NoError(t, err)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("logs deprecated names by default", func(t *testing.T) {
+		os.Unsetenv("NEW_VAR")
+		os.Setenv("OLD_VAR", "old")
+		defer os.Unsetenv("OLD_VAR")
+
+		var logs bytes.Buffer
+		defer slog.SetDefault(slog.Default())
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+		result, err := String("NEW_VAR").Deprecated("OLD_VAR").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "old", result)
+		assert.Contains(t, logs.String(), "variable=OLD_VAR use=NEW_VAR")
+	})
+}
+
 func TestVariableLoad(t *testing.T) {
 	t.Run("successfully loads variable", func(t *testing.T) {
 		os.Setenv("TEST_VAR", "hello")
@@ -304,6 +411,84 @@ func TestVariableLoad(t *testing.T) {
 	})
 }
 
+func TestVariableBuilder(t *testing.T) {
+	t.Run("loads parsed url with constraints", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "https://example.com/hook")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := ParsedUrl("TEST_VAR").Schemes("https").RequirePath().NoUserinfo().Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "/hook", result.Path)
+	})
+
+	t.Run("rejects url with disallowed scheme", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "http://example.com/hook")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := Url("TEST_VAR").Schemes("https").Load()
+		assert.ErrorIs(t, err, ErrInvalidUrl)
+		assert.Equal(t, "", result)
+	})
+
+	t.Run("rejects url options on other types", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "8080")
+		defer os.Unsetenv("TEST_VAR")
+
+		_, err := Int("TEST_VAR").Schemes("https").Load()
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+
+	t.Run("loads time with layout", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "02:30")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := Time("TEST_VAR").Layout("15:04").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Hour())
+		assert.Equal(t, 30, result.Minute())
+	})
+
+	t.Run("loads oneof ignoring case", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "STAGING")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := String("TEST_VAR").OneofFold("dev", "staging").Normalize().Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", result)
+	})
+
+	t.Run("loads json", func(t *testing.T) {
+		os.Setenv("TEST_VAR", `{"beta":true,"dark":false}`)
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := JSON[map[string]bool]("TEST_VAR").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"beta": true, "dark": false}, result)
+	})
+
+	t.Run("loads location", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "UTC")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := Location("TEST_VAR").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, "UTC", result.String())
+	})
+
+	t.Run("loads base64 as bytes", func(t *testing.T) {
+		os.Setenv("TEST_VAR", "AP8Q")
+		defer os.Unsetenv("TEST_VAR")
+
+		result, err := Base64("TEST_VAR").Load()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x00, 0xff, 0x10}, result)
+
+		os.Setenv("TEST_VAR", "not base64!")
+		_, err = Base64("TEST_VAR").Load()
+		assert.ErrorIs(t, err, ErrInvalidBase64)
+	})
+}
+
 func TestVariableMustLoad(t *testing.T) {
 	t.Run("returns value on success", func(t *testing.T) {
 		os.Setenv("TEST_VAR", "success")
@@ -0,0 +1,225 @@
+package environ
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// filePollInterval is how often the files given to WithFiles are checked for changes
+var filePollInterval = time.Second
+
+// Change is a configuration published by Watch
+type Change[T any] struct {
+	Config T
+	// Fields are the paths of the fields that changed since the previous configuration (e.g. "DB.Host")
+	Fields []string
+	// Err is set when the reload failed, Config is then the previous configuration.
+	// It's ErrRestartRequired when static fields changed, the other fields are then applied.
+	Err error
+}
+
+// Watch loads T and reloads it when a SIGHUP is received, or as configured by WithSignals, WithInterval and WithFiles.
+// The loaded configuration is published first, then every configuration that changed, until ctx is done.
+// A reload that fails is published with its error and the previous configuration, which is kept.
+// Static fields are never reloaded, changing them is published with ErrRestartRequired.
+// The same static change or reload error is only published once in a row.
+func Watch[T any](ctx context.Context, opts ...Option) (<-chan Change[T], error) {
+	r, err := newReloader[T](newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan Change[T], 1)
+	changes <- Change[T]{Config: r.config}
+
+	reloads := r.opts.reloads(ctx)
+	go func() {
+		defer close(changes)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloads:
+			}
+
+			change, publish := r.reload()
+			if !publish {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case changes <- change:
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// reloader reloads T and tells which reloads are worth publishing
+type reloader[T any] struct {
+	opts options
+	// config is the current configuration
+	config T
+
+	// static holds the values of the changed static fields that were last published, by path
+	static map[string]any
+	// err is the message of the last published error, empty when the last reload succeeded
+	err string
+}
+
+func newReloader[T any](opts options) (*reloader[T], error) {
+	config, err := load[T](opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloader[T]{opts: opts, config: config}, nil
+}
+
+// reload loads T again and returns its change from the current configuration, which it replaces.
+// The change should be published when fields changed, when the reload failed with another error than the last one,
+// or when the changed static fields differ from the last published ones.
+// Static fields keep their current value, and changing them is reported with ErrRestartRequired.
+func (r *reloader[T]) reload() (Change[T], bool) {
+	change := Change[T]{Config: r.config}
+
+	next, err := load[T](r.opts)
+	if err != nil {
+		change.Err = err
+		publish := err.Error() != r.err
+		r.err = err.Error()
+		return change, publish
+	}
+	r.err = ""
+
+	changed, static := changedFields(&r.config, &next, r.opts)
+	publish := !reflect.DeepEqual(static, r.static)
+	r.static = static
+
+	if len(static) > 0 {
+		fields := slices.Sorted(maps.Keys(static))
+		change.Err = fmt.Errorf("%w. static fields changed: %s", ErrRestartRequired, strings.Join(fields, ", "))
+	}
+
+	if len(changed) > 0 {
+		r.config = next
+		change.Config, change.Fields = next, changed
+	}
+
+	return change, publish || len(changed) > 0
+}
+
+// changedFields returns the paths of the fields declaring a variable that differ between the two configurations.
+// The static fields that differ are reverted to their previous value in next, and returned apart with their new value.
+func changedFields[T any](prev, next *T, opts options) (changed []string, static map[string]any) {
+	prevFields, _, _ := walkStruct(reflect.ValueOf(prev).Elem(), "", opts)
+	nextFields, _, _ := walkStruct(reflect.ValueOf(next).Elem(), "", opts)
+
+	for i, field := range nextFields {
+		value := field.Value.Interface()
+		if reflect.DeepEqual(prevFields[i].Value.Interface(), value) {
+			continue
+		}
+
+		if field.Variable.Static {
+			if static == nil {
+				static = map[string]any{}
+			}
+
+			static[field.Path] = value
+			field.Value.Set(prevFields[i].Value)
+		} else {
+			changed = append(changed, field.Path)
+		}
+	}
+
+	return changed, static
+}
+
+// reloads returns a channel receiving a value every time the configuration should be reloaded, until ctx is done
+func (o options) reloads(ctx context.Context) <-chan struct{} {
+	reloads := make(chan struct{}, 1)
+	reload := func() {
+		select {
+		case reloads <- struct{}{}:
+		default:
+		}
+	}
+
+	if len(o.signals) > 0 {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, o.signals...)
+
+		go func() {
+			defer signal.Stop(signals)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-signals:
+					reload()
+				}
+			}
+		}()
+	}
+
+	if o.interval > 0 {
+		go tick(ctx, o.interval, reload)
+	}
+
+	if len(o.files) > 0 {
+		states := fileStates(o.files)
+		go tick(ctx, filePollInterval, func() {
+			if next := fileStates(o.files); !reflect.DeepEqual(states, next) {
+				states = next
+				reload()
+			}
+		})
+	}
+
+	return reloads
+}
+
+// tick calls fn at every interval until ctx is done
+func tick(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+// fileState is what's compared to tell if a file changed
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func fileStates(paths []string) []fileState {
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			states[i] = fileState{true, info.Size(), info.ModTime()}
+		}
+	}
+
+	return states
+}
@@ -0,0 +1,241 @@
+package environ
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWatchConfig struct {
+	Level string `env:"name=TEST_LOG_LEVEL, oneof=debug|info, default=info"`
+	Limit int    `env:"name=TEST_RATE_LIMIT, type=int, default=10"`
+}
+
+// nextChange returns the next change published on the channel, or fails after a second
+func nextChange[T any](t *testing.T, changes <-chan Change[T]) Change[T] {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(time.Second):
+		t.Fatal("no change published")
+		return Change[T]{}
+	}
+}
+
+// noChange fails if a change is published on the channel within the duration
+func noChange[T any](t *testing.T, changes <-chan Change[T], d time.Duration) {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected change published: %+v", change)
+	case <-time.After(d):
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("publishes the loaded configuration first", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals())
+		assert.NoError(t, err)
+
+		change := nextChange(t, changes)
+		assert.Equal(t, testWatchConfig{Level: "info", Limit: 10}, change.Config)
+		assert.Empty(t, change.Fields)
+		assert.NoError(t, change.Err)
+	})
+
+	t.Run("returns error when the first load fails", func(t *testing.T) {
+		os.Setenv("TEST_RATE_LIMIT", "abc")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		_, err := Watch[testWatchConfig](context.Background(), WithSignals())
+		assert.ErrorIs(t, err, ErrInvalidInt)
+	})
+
+	t.Run("publishes changed fields on interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+
+		change := nextChange(t, changes)
+		assert.NoError(t, change.Err)
+		assert.Equal(t, "debug", change.Config.Level)
+		assert.Equal(t, []string{"Level"}, change.Fields)
+	})
+
+	t.Run("keeps the previous configuration when the reload fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_LOG_LEVEL", "trace")
+
+		change := nextChange(t, changes)
+		assert.ErrorIs(t, change.Err, ErrNotInOneof)
+		assert.Equal(t, "info", change.Config.Level)
+	})
+
+	t.Run("publishes the same reload error once", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_LOG_LEVEL")
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_LOG_LEVEL", "trace")
+		assert.ErrorIs(t, nextChange(t, changes).Err, ErrNotInOneof)
+		noChange(t, changes, 100*time.Millisecond)
+
+		// another error is published
+		os.Setenv("TEST_LOG_LEVEL", "info")
+		os.Setenv("TEST_RATE_LIMIT", "abc")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+		assert.ErrorIs(t, nextChange(t, changes).Err, ErrInvalidInt)
+		noChange(t, changes, 100*time.Millisecond)
+
+		// the same error is published again after a successful reload
+		os.Unsetenv("TEST_RATE_LIMIT")
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+		assert.NoError(t, nextChange(t, changes).Err)
+		os.Setenv("TEST_RATE_LIMIT", "abc")
+		assert.ErrorIs(t, nextChange(t, changes).Err, ErrInvalidInt)
+	})
+
+	t.Run("publishes restart required when static fields change", func(t *testing.T) {
+		type Config struct {
+			Port  int `env:"name=TEST_PORT, type=port, default=8080, static"`
+			Limit int `env:"name=TEST_RATE_LIMIT, type=int, default=10"`
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_PORT")
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		changes, err := Watch[Config](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_RATE_LIMIT", "20")
+		os.Setenv("TEST_PORT", "9090")
+
+		change := nextChange(t, changes)
+		for change.Err == nil {
+			// the rate limit was reloaded before the port was set
+			change = nextChange(t, changes)
+		}
+
+		assert.ErrorIs(t, change.Err, ErrRestartRequired)
+		assert.Equal(t, Config{Port: 8080, Limit: 20}, change.Config)
+	})
+
+	t.Run("publishes a static change once", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=TEST_PORT, type=port, default=8080, static"`
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_PORT")
+
+		changes, err := Watch[Config](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_PORT", "9090")
+		assert.ErrorIs(t, nextChange(t, changes).Err, ErrRestartRequired)
+		noChange(t, changes, 100*time.Millisecond)
+
+		// changing it again is a new static change
+		os.Setenv("TEST_PORT", "9091")
+		assert.ErrorIs(t, nextChange(t, changes).Err, ErrRestartRequired)
+		noChange(t, changes, 100*time.Millisecond)
+
+		// going back to the loaded value needs no restart anymore
+		os.Unsetenv("TEST_PORT")
+		change := nextChange(t, changes)
+		assert.NoError(t, change.Err)
+		assert.Equal(t, Config{Port: 8080}, change.Config)
+		noChange(t, changes, 100*time.Millisecond)
+	})
+
+	t.Run("reloads on signal", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(syscall.SIGHUP))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_RATE_LIMIT", "20")
+		process, err := os.FindProcess(os.Getpid())
+		assert.NoError(t, err)
+		assert.NoError(t, process.Signal(syscall.SIGHUP))
+
+		change := nextChange(t, changes)
+		assert.Equal(t, 20, change.Config.Limit)
+		assert.Equal(t, []string{"Limit"}, change.Fields)
+	})
+
+	t.Run("reloads when a file changes", func(t *testing.T) {
+		defer func(interval time.Duration) { filePollInterval = interval }(filePollInterval)
+		filePollInterval = 10 * time.Millisecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		defer os.Unsetenv("TEST_RATE_LIMIT")
+
+		file := filepath.Join(t.TempDir(), "config")
+		assert.NoError(t, os.WriteFile(file, []byte("a"), 0o644))
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(), WithFiles(file))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		os.Setenv("TEST_RATE_LIMIT", "30")
+		assert.NoError(t, os.WriteFile(file, []byte("ab"), 0o644))
+
+		change := nextChange(t, changes)
+		assert.Equal(t, 30, change.Config.Limit)
+	})
+
+	t.Run("closes the channel when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		changes, err := Watch[testWatchConfig](ctx, WithSignals(), WithInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		nextChange(t, changes)
+
+		cancel()
+
+		select {
+		case _, ok := <-changes:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed")
+		}
+	})
+}